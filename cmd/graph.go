@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"github.com/palantir/godel-mod-plugin/gomod"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphFormatFlagVal       string
+	graphDepthFlagVal        int
+	graphDependenciesFlagVal string
+	graphPrefixesFlagVal     []string
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "mod-graph [flags]",
+	Short: "Prints the dependency graph of every module in the project",
+	Long: `Prints the graph of the selected build list of every module in the project based on "go mod graph" and
+"go list -m -json all". Every module in the build list is a single node at its selected version. The graph can be
+limited by depth, to direct or indirect dependencies and to module path prefixes, and is rendered as Graphviz DOT,
+Mermaid or JSON.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := modParams()
+		if err != nil {
+			return err
+		}
+		format, err := gomod.ParseGraphFormat(graphFormatFlagVal)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for --format")
+		}
+		dependencies, err := gomod.ParseDependencyFilter(graphDependenciesFlagVal)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for --dependencies")
+		}
+		ctx, cancel := modContext(timeoutFlagVal)
+		defer cancel()
+
+		graph, err := gomod.Graph(ctx, projectDirFlagVal, gomod.GraphParams{
+			MaxDepth:     graphDepthFlagVal,
+			Dependencies: dependencies,
+			Prefixes:     graphPrefixesFlagVal,
+		}, params)
+		if err != nil {
+			return err
+		}
+		return graph.Write(cmd.OutOrStdout(), format)
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphFormatFlagVal, "format", string(gomod.GraphFormatDOT), `format of the graph: "dot", "mermaid" or "json"`)
+	graphCmd.Flags().IntVar(&graphDepthFlagVal, "depth", 0, "maximum number of requirements between the main module and a dependency (0 means no limit)")
+	graphCmd.Flags().StringVar(&graphDependenciesFlagVal, "dependencies", string(gomod.DependencyFilterAll), `dependencies that are included: "all", "direct" or "indirect"`)
+	graphCmd.Flags().StringSliceVar(&graphPrefixesFlagVal, "prefix", nil, "if non-empty, only the dependencies whose module paths start with one of the prefixes are included")
+	graphCmd.Flags().DurationVar(&timeoutFlagVal, "timeout", 0, "maximum amount of time the task may run for (0 means no limit)")
+	rootCmd.AddCommand(graphCmd)
+}
@@ -5,21 +5,123 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/palantir/godel-mod-plugin/gomod"
+	"github.com/palantir/godel-mod-plugin/gomod/config"
+	godelconfig "github.com/palantir/godel/v2/framework/godel/config"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 var modCmd = &cobra.Command{
 	Use:   "mod [flags] [args]",
 	Short: "Ensures that the go module state for the project is up-to-date",
-	Long: `Executes "go mod tidy" followed by "go mod vendor" to ensure that the module state for the repository is
-up-to-date. When run in verification mode, fails if either operation resulted in project state being modified.`,
+	Long: `Executes "go mod tidy" followed by "go mod vendor" for every module in the project to ensure that the module
+state for the repository is up-to-date. When run in verification mode, fails if either operation would result in
+project state being modified.
+
+The go.mod, go.sum and vendor files of the project are only modified once the go commands for every module have
+succeeded. If a command fails, the process is interrupted or the timeout specified by --timeout is reached, the running
+go command is killed and the project is left in the state it was in before the task started.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return gomod.Run(projectDirFlagVal, verifyFlagVal, cmd.OutOrStdout())
+		params, err := modParams()
+		if err != nil {
+			return err
+		}
+		if outputFormatFlagVal != outputFormatText && outputFormatFlagVal != outputFormatJSON {
+			return errors.Errorf("invalid value for --output-format: must be %q or %q, was %q", outputFormatText, outputFormatJSON, outputFormatFlagVal)
+		}
+		ctx, cancel := modContext(timeoutFlagVal)
+		defer cancel()
+
+		// in JSON mode, the output of the go commands is recorded in the report rather than printed
+		taskOutput := cmd.OutOrStdout()
+		if outputFormatFlagVal == outputFormatJSON {
+			taskOutput = io.Discard
+		}
+		report, runErr := gomod.RunWithReport(ctx, projectDirFlagVal, verifyFlagVal, params, taskOutput)
+		if outputFormatFlagVal == outputFormatJSON {
+			if err := report.WriteJSON(cmd.OutOrStdout()); err != nil {
+				return err
+			}
+		}
+		if reportFileFlagVal != "" {
+			if err := writeReportFile(reportFileFlagVal, report.WriteJSON); err != nil {
+				return err
+			}
+		}
+		if junitFileFlagVal != "" {
+			if err := writeReportFile(junitFileFlagVal, report.WriteJUnit); err != nil {
+				return err
+			}
+		}
+		return runErr
 	},
 }
 
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// writeReportFile writes the output of the provided write function to the file at path.
+func writeReportFile(path string, write func(io.Writer) error) error {
+	buf := &bytes.Buffer{}
+	if err := write(buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write report to %s", path)
+	}
+	return nil
+}
+
+// modParams returns the gomod.Params specified by the plugin configuration file and the excludes specified by the
+// gödel configuration file.
+func modParams() (gomod.Params, error) {
+	cfg, err := config.ReadConfigFromFile(configFileFlagVal)
+	if err != nil {
+		return gomod.Params{}, err
+	}
+	params, err := cfg.ToParams()
+	if err != nil {
+		return gomod.Params{}, err
+	}
+	godelExcludes, err := godelconfig.ReadGodelConfigExcludesFromFile(godelConfigFileFlagVal)
+	if err != nil {
+		return gomod.Params{}, err
+	}
+	params.Exclude = godelExcludes.Matcher()
+	params.Debug = debugFlagVal
+	return params, nil
+}
+
+// modContext returns a context that is cancelled when the process receives SIGINT or SIGTERM or, if timeout is
+// positive, when the timeout elapses.
+func modContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
 func init() {
 	modCmd.Flags().BoolVar(&verifyFlagVal, "verify", false, "verify that go module state is up-to-date")
+	modCmd.Flags().StringVar(&outputFormatFlagVal, "output-format", outputFormatText, `format of the output: "text" prints the output of the go commands and "json" prints a JSON report`)
+	modCmd.Flags().StringVar(&reportFileFlagVal, "report-file", "", "if non-empty, path to which a JSON report of the run is written")
+	modCmd.Flags().StringVar(&junitFileFlagVal, "junit-file", "", "if non-empty, path to which a JUnit XML report of the verification checks is written")
+	modCmd.Flags().DurationVar(&timeoutFlagVal, "timeout", 0, "maximum amount of time the task may run for (0 means no limit)")
 	rootCmd.AddCommand(modCmd)
 }
@@ -0,0 +1,49 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"github.com/palantir/godel-mod-plugin/gomod"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var directOnlyFlagVal bool
+
+var outdatedCmd = &cobra.Command{
+	Use:   "mod-outdated [flags]",
+	Short: "Lists the requirements of every module in the project that have newer versions available",
+	Long: `Runs "go list -m -u -json all" for every module in the project and lists the requirements for which a newer
+patch, minor or major version is available along with the latest version with the same module path and the latest
+newer major version. The versions are resolved using the module proxy configured by GOPROXY, which may be a local
+"file://" mirror. Does not modify the project.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := modParams()
+		if err != nil {
+			return err
+		}
+		if outputFormatFlagVal != outputFormatText && outputFormatFlagVal != outputFormatJSON {
+			return errors.Errorf("invalid value for --output-format: must be %q or %q, was %q", outputFormatText, outputFormatJSON, outputFormatFlagVal)
+		}
+		ctx, cancel := modContext(timeoutFlagVal)
+		defer cancel()
+
+		report, err := gomod.Outdated(ctx, projectDirFlagVal, directOnlyFlagVal, params)
+		if err != nil {
+			return err
+		}
+		if outputFormatFlagVal == outputFormatJSON {
+			return report.WriteJSON(cmd.OutOrStdout())
+		}
+		return report.WriteTable(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	outdatedCmd.Flags().BoolVar(&directOnlyFlagVal, "direct-only", false, "only list the modules that are required directly")
+	outdatedCmd.Flags().StringVar(&outputFormatFlagVal, "output-format", outputFormatText, `format of the output: "text" prints a table and "json" prints a JSON report`)
+	outdatedCmd.Flags().DurationVar(&timeoutFlagVal, "timeout", 0, "maximum amount of time the task may run for (0 means no limit)")
+	rootCmd.AddCommand(outdatedCmd)
+}
@@ -19,6 +19,8 @@ var (
 		pluginapi.PluginInfoGlobalFlagOptions(
 			pluginapi.GlobalFlagOptionsParamDebugFlag("--"+pluginapi.DebugFlagName),
 			pluginapi.GlobalFlagOptionsParamProjectDirFlag("--"+pluginapi.ProjectDirFlagName),
+			pluginapi.GlobalFlagOptionsParamGodelConfigFlag("--"+pluginapi.GodelConfigFlagName),
+			pluginapi.GlobalFlagOptionsParamConfigFlag("--"+pluginapi.ConfigFlagName),
 		),
 		pluginapi.PluginInfoTaskInfo(
 			"mod",
@@ -29,5 +31,28 @@ var (
 				pluginapi.VerifyOptionsOrdering(new(verifyorder.Format+50)),
 			),
 		),
+		pluginapi.PluginInfoTaskInfo(
+			"mod-outdated",
+			"List the requirements that have newer versions available",
+			pluginapi.TaskInfoCommand("mod-outdated"),
+		),
+		pluginapi.PluginInfoTaskInfo(
+			"mod-upgrade",
+			"Upgrade the dependencies of every module and run 'go mod tidy' and 'go mod vendor'",
+			pluginapi.TaskInfoCommand("mod-upgrade"),
+		),
+		pluginapi.PluginInfoTaskInfo(
+			"mod-why",
+			"Explain why modules are part of the build list",
+			pluginapi.TaskInfoCommand("mod-why"),
+		),
+		pluginapi.PluginInfoTaskInfo(
+			"mod-graph",
+			"Print the dependency graph in DOT, Mermaid or JSON",
+			pluginapi.TaskInfoCommand("mod-graph"),
+		),
+		pluginapi.PluginInfoUpgradeConfigTaskInfo(
+			pluginapi.UpgradeConfigTaskInfoCommand("upgrade-config"),
+		),
 	)
 )
@@ -5,15 +5,24 @@
 package cmd
 
 import (
+	"time"
+
+	"github.com/palantir/godel-mod-plugin/gomod/config"
 	"github.com/palantir/godel/v2/framework/pluginapi"
 	"github.com/palantir/pkg/cobracli"
 	"github.com/spf13/cobra"
 )
 
 var (
-	debugFlagVal      bool
-	projectDirFlagVal string
-	verifyFlagVal     bool
+	debugFlagVal           bool
+	projectDirFlagVal      string
+	godelConfigFileFlagVal string
+	configFileFlagVal      string
+	verifyFlagVal          bool
+	timeoutFlagVal         time.Duration
+	outputFormatFlagVal    string
+	reportFileFlagVal      string
+	junitFileFlagVal       string
 )
 
 var rootCmd = &cobra.Command{
@@ -28,4 +37,8 @@ func Execute() int {
 func init() {
 	pluginapi.AddDebugPFlagPtr(rootCmd.PersistentFlags(), &debugFlagVal)
 	pluginapi.AddProjectDirPFlagPtr(rootCmd.PersistentFlags(), &projectDirFlagVal)
+	pluginapi.AddGodelConfigPFlagPtr(rootCmd.PersistentFlags(), &godelConfigFileFlagVal)
+	pluginapi.AddConfigPFlagPtr(rootCmd.PersistentFlags(), &configFileFlagVal)
+
+	rootCmd.AddCommand(pluginapi.CobraUpgradeConfigCmd(config.UpgradeConfig))
 }
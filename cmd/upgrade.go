@@ -0,0 +1,54 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"github.com/palantir/godel-mod-plugin/gomod"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var strategyFlagVal string
+
+var upgradeCmd = &cobra.Command{
+	Use:   "mod-upgrade [flags] [module patterns]",
+	Short: "Upgrades the dependencies of every module in the project",
+	Long: `Upgrades the dependencies of every module in the project using "go get" according to the strategy specified by
+--strategy: "patch" upgrades every dependency to the latest patch version of its current minor version and "minor"
+upgrades every dependency to the latest version with the same module path. "major" upgrades the dependencies that match
+the module patterns (which are required for this strategy) to their latest newer major version (such as
+"github.com/pkg/errors/v3") and rewrites the import paths of their packages in the Go source files of the project. If
+module patterns are provided, only the matching modules are upgraded. The modules that match upgrade.exclude in the configuration are never upgraded directly.
+
+After upgrading, "go mod tidy" and "go mod vendor" (if vendoring is enabled) are run as they are by the mod task and
+the version changes are printed. If either operation or the "go build ./..." check enabled by upgrade.build-check
+fails, the go.mod, go.sum and vendor files and the rewritten Go source files of the project are restored.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := modParams()
+		if err != nil {
+			return err
+		}
+		strategy, err := gomod.ParseUpgradeStrategy(strategyFlagVal)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for --strategy")
+		}
+		for i, pattern := range args {
+			if err := gomod.ValidateModulePattern(pattern); err != nil {
+				return errors.Wrapf(err, "invalid module pattern argument %d", i)
+			}
+		}
+		ctx, cancel := modContext(timeoutFlagVal)
+		defer cancel()
+
+		_, err = gomod.Upgrade(ctx, projectDirFlagVal, strategy, args, params, cmd.OutOrStdout())
+		return err
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&strategyFlagVal, "strategy", string(gomod.UpgradeStrategyMinor), `versions that dependencies are upgraded to: "patch", "minor" or "major"`)
+	upgradeCmd.Flags().DurationVar(&timeoutFlagVal, "timeout", 0, "maximum amount of time the task may run for (0 means no limit)")
+	rootCmd.AddCommand(upgradeCmd)
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"github.com/palantir/godel-mod-plugin/gomod"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var whyCmd = &cobra.Command{
+	Use:   "mod-why [flags] <module paths>",
+	Short: "Explains why modules are part of the build list of every module in the project",
+	Long: `Prints the shortest import chain from a package of every module in the project to a package of each of the
+provided modules (as reported by "go mod why -m") and the shortest chain of module requirements that adds the module
+to the build list (based on "go mod graph").`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := modParams()
+		if err != nil {
+			return err
+		}
+		if outputFormatFlagVal != outputFormatText && outputFormatFlagVal != outputFormatJSON {
+			return errors.Errorf("invalid value for --output-format: must be %q or %q, was %q", outputFormatText, outputFormatJSON, outputFormatFlagVal)
+		}
+		ctx, cancel := modContext(timeoutFlagVal)
+		defer cancel()
+
+		report, err := gomod.Why(ctx, projectDirFlagVal, args, params)
+		if err != nil {
+			return err
+		}
+		if outputFormatFlagVal == outputFormatJSON {
+			return report.WriteJSON(cmd.OutOrStdout())
+		}
+		return report.WriteText(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	whyCmd.Flags().StringVar(&outputFormatFlagVal, "output-format", outputFormatText, `format of the output: "text" prints the chains and "json" prints a JSON report`)
+	whyCmd.Flags().DurationVar(&timeoutFlagVal, "timeout", 0, "maximum amount of time the task may run for (0 means no limit)")
+	rootCmd.AddCommand(whyCmd)
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+)
+
+// buildListModule is a module in the build list as reported by "go list -m -json".
+type buildListModule struct {
+	Path      string
+	Version   string
+	Main      bool
+	Indirect  bool
+	GoVersion string
+	Replace   *buildListModule
+	// Update is the latest version of the module with the same module path, which is only reported by "go list -u".
+	Update *buildListModule
+	// Error is the error that occurred while loading the module, which is only reported by "go list -e".
+	Error *struct {
+		Err string
+	}
+}
+
+// String returns the module path and version (for example, "github.com/pkg/errors@v0.9.1") followed by its
+// replacement, if any.
+func (m buildListModule) String() string {
+	s := moduleVersionString(m.Path, m.Version)
+	if m.Replace != nil {
+		s += " => " + moduleVersionString(m.Replace.Path, m.Replace.Version)
+	}
+	return s
+}
+
+// listBuildList returns the build list of the module in dir using the go.mod file at goModPath. The first module is the
+// main module.
+func listBuildList(ctx context.Context, executor Executor, dir, goModPath string) ([]buildListModule, error) {
+	return listModules(ctx, executor, dir, "-mod=mod", "-modfile="+goModPath, "all")
+}
+
+// listModules returns the modules reported by "go list -m -json" with the provided flags and arguments for the module in
+// dir.
+func listModules(ctx context.Context, executor Executor, dir string, args ...string) ([]buildListModule, error) {
+	args = append([]string{"list", "-m", "-json"}, args...)
+	output := &bytes.Buffer{}
+	if _, err := execute(ctx, executor, dir, moduleEnv, output, args...); err != nil {
+		return nil, err
+	}
+	var modules []buildListModule
+	decoder := json.NewDecoder(output)
+	for {
+		var mod buildListModule
+		if err := decoder.Decode(&mod); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal output of go %v as JSON", args)
+		}
+		modules = append(modules, mod)
+	}
+	return modules, nil
+}
+
+// importChains returns the shortest import chain from a package in the main module to a package of each of the
+// provided modules as reported by "go mod why -m" using the go.mod file at goModPath. The chain of a module that is
+// not needed by any package in the main module is nil.
+func importChains(ctx context.Context, executor Executor, dir, goModPath string, modPaths []string) (map[string][]string, error) {
+	output := &bytes.Buffer{}
+	if _, err := execute(ctx, executor, dir, moduleEnv, output, append([]string{"mod", "why", "-m", "-modfile=" + goModPath}, modPaths...)...); err != nil {
+		return nil, err
+	}
+	return parseModWhy(output.Bytes()), nil
+}
+
+// parseModWhy parses the output of "go mod why -m", which consists of a section that starts with "# <module>" for each
+// module followed by the import chain (one package per line) or by a line in parentheses if the module is not needed.
+func parseModWhy(output []byte) map[string][]string {
+	chains := make(map[string][]string)
+	var current string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "# "):
+			current = strings.TrimPrefix(line, "# ")
+			chains[current] = nil
+		case line == "" || strings.HasPrefix(line, "(") || current == "":
+			continue
+		default:
+			chains[current] = append(chains[current], line)
+		}
+	}
+	return chains
+}
+
+// requireLines returns the location and content of the require directive of every module required by the provided
+// go.mod file (for example, "go.mod:7: require github.com/pkg/errors v0.9.1 // indirect"), keyed by module path.
+// relPath is the path of the go.mod file used in the location.
+func requireLines(relPath string, goMod []byte) (map[string]string, error) {
+	modFile, err := modfile.Parse(relPath, goMod, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", relPath)
+	}
+	lines := make(map[string]string, len(modFile.Require))
+	for _, req := range modFile.Require {
+		line := fmt.Sprintf("%s:%d: require %s %s", relPath, req.Syntax.Start.Line, req.Mod.Path, req.Mod.Version)
+		if req.Indirect {
+			line += " // indirect"
+		}
+		lines[req.Mod.Path] = line
+	}
+	return lines, nil
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/palantir/godel-mod-plugin/gomod"
+	v0 "github.com/palantir/godel-mod-plugin/gomod/config/internal/v0"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+type Config v0.Config
+
+func ToConfig(in *Config) *v0.Config {
+	return (*v0.Config)(in)
+}
+
+// ReadConfigFromFile reads the mod-plugin configuration from the provided file and returns the loaded configuration.
+// Returns an empty configuration if cfgFile is empty or the file does not exist.
+func ReadConfigFromFile(cfgFile string) (Config, error) {
+	if cfgFile == "" {
+		return Config{}, nil
+	}
+	cfgBytes, err := os.ReadFile(cfgFile)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, errors.Wrapf(err, "failed to read file %s", cfgFile)
+	}
+	return ReadConfig(cfgBytes)
+}
+
+// ReadConfig upgrades and validates the provided mod-plugin configuration and returns the loaded configuration.
+func ReadConfig(cfgBytes []byte) (Config, error) {
+	upgradedBytes, err := UpgradeConfig(cfgBytes)
+	if err != nil {
+		return Config{}, errors.Wrapf(err, "failed to upgrade configuration")
+	}
+	var cfg Config
+	if err := yaml.UnmarshalStrict(upgradedBytes, &cfg); err != nil {
+		return Config{}, errors.Wrapf(err, "failed to unmarshal mod-plugin configuration")
+	}
+	return cfg, nil
+}
+
+// ToParams returns the gomod.Params represented by the configuration. Returns an error if the configuration contains
+// invalid values.
+func (c *Config) ToParams() (gomod.Params, error) {
+	vendorMode, err := gomod.ParseVendorMode(c.VendorMode)
+	if err != nil {
+		return gomod.Params{}, errors.Wrapf(err, "invalid value for vendor-mode")
+	}
+	if c.Concurrency < 0 {
+		return gomod.Params{}, errors.Errorf("invalid value for concurrency: must be non-negative, was %d", c.Concurrency)
+	}
+	if err := gomod.ValidateGoToolchain(c.Toolchain.GoToolchain); err != nil {
+		return gomod.Params{}, errors.Wrapf(err, "invalid value for toolchain.go-toolchain")
+	}
+	if err := gomod.ValidateToolchainDirective(c.Toolchain.Directive); err != nil {
+		return gomod.Params{}, errors.Wrapf(err, "invalid value for toolchain.directive")
+	}
+	denyRules, err := toModuleRules("policy.modules.deny", c.Policy.Modules.Deny)
+	if err != nil {
+		return gomod.Params{}, err
+	}
+	allowRules, err := toModuleRules("policy.modules.allow", c.Policy.Modules.Allow)
+	if err != nil {
+		return gomod.Params{}, err
+	}
+	for i, pattern := range c.Policy.Replace.AllowedLocalPaths {
+		if err := gomod.ValidateLocalPathPattern(pattern); err != nil {
+			return gomod.Params{}, errors.Wrapf(err, "invalid value for policy.replace.allowed-local-paths[%d]", i)
+		}
+	}
+	for i, pattern := range c.Policy.Replace.AllowedForks {
+		if err := gomod.ValidateModulePattern(pattern); err != nil {
+			return gomod.Params{}, errors.Wrapf(err, "invalid value for policy.replace.allowed-forks[%d]", i)
+		}
+	}
+	stabilityRules, err := toStabilityRules(c.Policy.Stability)
+	if err != nil {
+		return gomod.Params{}, err
+	}
+	goVersionPolicy := gomod.GoVersionPolicyParams{
+		Min:     c.Policy.GoVersion.Min,
+		Max:     c.Policy.GoVersion.Max,
+		Align:   c.Policy.GoVersion.Align,
+		MaxSkew: c.Policy.GoVersion.MaxSkew,
+	}
+	if err := goVersionPolicy.Validate(); err != nil {
+		return gomod.Params{}, errors.Wrapf(err, "invalid value for policy.go-version")
+	}
+	for i, pattern := range c.Upgrade.Exclude {
+		if err := gomod.ValidateModulePattern(pattern); err != nil {
+			return gomod.Params{}, errors.Wrapf(err, "invalid value for upgrade.exclude[%d]", i)
+		}
+	}
+	return gomod.Params{
+		VendorMode:  vendorMode,
+		Concurrency: c.Concurrency,
+		Tidy: gomod.TidyParams{
+			Compat:       c.Tidy.Compat,
+			IgnoreErrors: c.Tidy.IgnoreErrors,
+		},
+		Verify: gomod.VerifyParams{
+			SkipGoSum:  c.Verify.SkipGoSum,
+			SkipVendor: c.Verify.SkipVendor,
+		},
+		Workspace: gomod.WorkspaceParams{
+			ForbidGoWork: c.Workspace.ForbidGoWork,
+		},
+		Toolchain: gomod.ToolchainParams{
+			GoBinary:    c.Toolchain.GoBinary,
+			GoToolchain: c.Toolchain.GoToolchain,
+			Directive:   c.Toolchain.Directive,
+		},
+		Policy: gomod.PolicyParams{
+			Modules: gomod.ModulePolicyParams{
+				Deny:  denyRules,
+				Allow: allowRules,
+			},
+			Replace: gomod.ReplacePolicyParams{
+				ForbidLocalPaths:  c.Policy.Replace.ForbidLocalPaths,
+				AllowedLocalPaths: c.Policy.Replace.AllowedLocalPaths,
+				AllowedForks:      c.Policy.Replace.AllowedForks,
+			},
+			Stability: stabilityRules,
+			GoVersion: goVersionPolicy,
+		},
+		Upgrade: gomod.UpgradeParams{
+			Exclude:    c.Upgrade.Exclude,
+			BuildCheck: c.Upgrade.BuildCheck,
+		},
+	}, nil
+}
+
+// toModuleRules returns the gomod.ModuleRule values represented by the provided rules. keyPath is the location of the
+// rules in the configuration, which is used in errors.
+func toModuleRules(keyPath string, rules []v0.ModuleRuleConfig) ([]gomod.ModuleRule, error) {
+	var moduleRules []gomod.ModuleRule
+	for i, rule := range rules {
+		if err := gomod.ValidateModulePattern(rule.Module); err != nil {
+			return nil, errors.Wrapf(err, "invalid value for %s[%d].module", keyPath, i)
+		}
+		versions, err := gomod.ParseVersionRange(rule.Versions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for %s[%d].versions", keyPath, i)
+		}
+		moduleRules = append(moduleRules, gomod.ModuleRule{
+			Module:   rule.Module,
+			Versions: versions,
+			Reason:   rule.Reason,
+		})
+	}
+	return moduleRules, nil
+}
+
+// toStabilityRules returns the gomod.StabilityRule values represented by the provided rules. A rule expires at the end
+// of the day (in UTC) specified by its expiration date.
+func toStabilityRules(rules []v0.StabilityRuleConfig) ([]gomod.StabilityRule, error) {
+	var stabilityRules []gomod.StabilityRule
+	for i, rule := range rules {
+		kind, err := gomod.ParseVersionKind(rule.Kind)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for policy.stability[%d].kind", i)
+		}
+		if rule.Module != "" {
+			if err := gomod.ValidateModulePattern(rule.Module); err != nil {
+				return nil, errors.Wrapf(err, "invalid value for policy.stability[%d].module", i)
+			}
+		}
+		action := gomod.StabilityActionFail
+		if rule.Action != "" {
+			if action, err = gomod.ParseStabilityAction(rule.Action); err != nil {
+				return nil, errors.Wrapf(err, "invalid value for policy.stability[%d].action", i)
+			}
+		}
+		var expires time.Time
+		if rule.Expires != "" {
+			date, err := time.Parse(time.DateOnly, rule.Expires)
+			if err != nil {
+				return nil, errors.Errorf("invalid value for policy.stability[%d].expires: %q is not a date in the format YYYY-MM-DD", i, rule.Expires)
+			}
+			expires = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		stabilityRules = append(stabilityRules, gomod.StabilityRule{
+			Kind:    kind,
+			Module:  rule.Module,
+			Action:  action,
+			Expires: expires,
+			Reason:  rule.Reason,
+		})
+	}
+	return stabilityRules, nil
+}
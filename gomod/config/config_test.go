@@ -0,0 +1,278 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/palantir/godel-mod-plugin/gomod"
+	"github.com/palantir/godel-mod-plugin/gomod/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadConfig(t *testing.T) {
+	cfg, err := config.ReadConfig([]byte(`
+vendor-mode: always
+tidy:
+  compat: "1.21"
+  ignore-errors: true
+verify:
+  skip-go-sum: true
+toolchain:
+  go-binary: /usr/local/go/bin/go
+  go-toolchain: go1.22.5
+  directive: go1.22.5
+`))
+	require.NoError(t, err)
+
+	params, err := cfg.ToParams()
+	require.NoError(t, err)
+	assert.Equal(t, gomod.Params{
+		VendorMode: gomod.VendorModeAlways,
+		Tidy: gomod.TidyParams{
+			Compat:       "1.21",
+			IgnoreErrors: true,
+		},
+		Verify: gomod.VerifyParams{
+			SkipGoSum: true,
+		},
+		Toolchain: gomod.ToolchainParams{
+			GoBinary:    "/usr/local/go/bin/go",
+			GoToolchain: "go1.22.5",
+			Directive:   "go1.22.5",
+		},
+	}, params)
+}
+
+func TestReadConfigErrors(t *testing.T) {
+	for i, tc := range []struct {
+		name    string
+		cfg     string
+		wantErr string
+	}{
+		{
+			name:    "unknown top-level key",
+			cfg:     "vendor: always\n",
+			wantErr: `failed to upgrade configuration: unknown key "vendor" in mod-plugin configuration: valid keys are concurrency, policy, tidy, toolchain, upgrade, vendor-mode, verify, version, workspace`,
+		},
+		{
+			name:    "unknown nested key",
+			cfg:     "tidy:\n  compatibility: \"1.21\"\n",
+			wantErr: `failed to upgrade configuration: unknown key "tidy.compatibility" in mod-plugin configuration: valid keys for "tidy" are compat, ignore-errors`,
+		},
+		{
+			name:    "unsupported version",
+			cfg:     "version: 1\n",
+			wantErr: `failed to upgrade configuration: unsupported version: 1`,
+		},
+	} {
+		_, err := config.ReadConfig([]byte(tc.cfg))
+		assert.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestToParamsInvalidVendorMode(t *testing.T) {
+	cfg, err := config.ReadConfig([]byte("vendor-mode: sometimes\n"))
+	require.NoError(t, err)
+
+	_, err = cfg.ToParams()
+	assert.EqualError(t, err, `invalid value for vendor-mode: "sometimes" is not a valid vendor mode: must be one of "auto", "always" or "never"`)
+}
+
+func TestToParamsInvalidGoToolchain(t *testing.T) {
+	cfg, err := config.ReadConfig([]byte("toolchain:\n  go-toolchain: 1.22.5\n"))
+	require.NoError(t, err)
+
+	_, err = cfg.ToParams()
+	assert.EqualError(t, err, `invalid value for toolchain.go-toolchain: "1.22.5" is not a valid GOTOOLCHAIN value: must be "auto", "local", "path" or a toolchain name such as "go1.22.5" optionally followed by "+auto" or "+path"`)
+}
+
+func TestToParamsInvalidToolchainDirective(t *testing.T) {
+	cfg, err := config.ReadConfig([]byte("toolchain:\n  directive: 1.22.5\n"))
+	require.NoError(t, err)
+
+	_, err = cfg.ToParams()
+	assert.EqualError(t, err, `invalid value for toolchain.directive: "1.22.5" is not a valid toolchain name: must be a toolchain name such as "go1.22.5"`)
+}
+
+func TestToParamsModulePolicy(t *testing.T) {
+	cfg, err := config.ReadConfig([]byte(`
+policy:
+  modules:
+    deny:
+      - module: github.com/sirupsen/logrus
+        reason: use the standard library log/slog package
+      - module: github.com/pkg/errors
+        versions: ">= v0.9.0, <v1.0.0"
+    allow:
+      - module: github.com/palantir/...
+`))
+	require.NoError(t, err)
+
+	params, err := cfg.ToParams()
+	require.NoError(t, err)
+	assert.Equal(t, gomod.ModulePolicyParams{
+		Deny: []gomod.ModuleRule{
+			{Module: "github.com/sirupsen/logrus", Reason: "use the standard library log/slog package"},
+			{Module: "github.com/pkg/errors", Versions: gomod.VersionRange{{Op: ">=", Version: "v0.9.0"}, {Op: "<", Version: "v1.0.0"}}},
+		},
+		Allow: []gomod.ModuleRule{
+			{Module: "github.com/palantir/..."},
+		},
+	}, params.Policy.Modules)
+}
+
+func TestToParamsInvalidModulePolicy(t *testing.T) {
+	for i, tc := range []struct {
+		name    string
+		cfg     string
+		wantErr string
+	}{
+		{
+			name:    "invalid pattern",
+			cfg:     "policy:\n  modules:\n    deny:\n      - module: \"github.com/[\"\n",
+			wantErr: `invalid value for policy.modules.deny[0].module: "github.com/[" is not a valid module pattern`,
+		},
+		{
+			name:    "invalid version range",
+			cfg:     "policy:\n  modules:\n    allow:\n      - module: github.com/pkg/errors\n        versions: \"~v1.0.0\"\n",
+			wantErr: `invalid value for policy.modules.allow[0].versions: "~v1.0.0" is not a valid version range: constraint "~v1.0.0" must start with one of <=, >=, !=, <, >, =`,
+		},
+	} {
+		cfg, err := config.ReadConfig([]byte(tc.cfg))
+		require.NoError(t, err, "Case %d: %s", i, tc.name)
+		_, err = cfg.ToParams()
+		assert.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestToParamsInvalidReplacePolicy(t *testing.T) {
+	for i, tc := range []struct {
+		name    string
+		cfg     string
+		wantErr string
+	}{
+		{
+			name:    "absolute local path",
+			cfg:     "policy:\n  replace:\n    forbid-local-paths: true\n    allowed-local-paths:\n      - /opt/shared\n",
+			wantErr: `invalid value for policy.replace.allowed-local-paths[0]: "/opt/shared" is not a valid path pattern: must be relative to the module directory`,
+		},
+		{
+			name:    "invalid fork pattern",
+			cfg:     "policy:\n  replace:\n    allowed-forks:\n      - \"github.com/[\"\n",
+			wantErr: `invalid value for policy.replace.allowed-forks[0]: "github.com/[" is not a valid module pattern`,
+		},
+	} {
+		cfg, err := config.ReadConfig([]byte(tc.cfg))
+		require.NoError(t, err, "Case %d: %s", i, tc.name)
+		_, err = cfg.ToParams()
+		assert.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestToParamsStabilityPolicy(t *testing.T) {
+	cfg, err := config.ReadConfig([]byte(`
+policy:
+  stability:
+    - kind: pseudo-version
+      module: github.com/palantir/...
+      action: allow
+      expires: 2026-12-31
+    - kind: pseudo-version
+    - kind: prerelease
+      action: warn
+`))
+	require.NoError(t, err)
+
+	params, err := cfg.ToParams()
+	require.NoError(t, err)
+	assert.Equal(t, []gomod.StabilityRule{
+		{Kind: gomod.VersionKindPseudo, Module: "github.com/palantir/...", Action: gomod.StabilityActionAllow, Expires: time.Date(2026, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+		{Kind: gomod.VersionKindPseudo, Action: gomod.StabilityActionFail},
+		{Kind: gomod.VersionKindPrerelease, Action: gomod.StabilityActionWarn},
+	}, params.Policy.Stability)
+}
+
+func TestToParamsInvalidStabilityPolicy(t *testing.T) {
+	for i, tc := range []struct {
+		name    string
+		cfg     string
+		wantErr string
+	}{
+		{
+			name:    "invalid kind",
+			cfg:     "policy:\n  stability:\n    - kind: alpha\n",
+			wantErr: `invalid value for policy.stability[0].kind: "alpha" is not a valid version kind: must be one of "pseudo-version", "prerelease", "incompatible" or "v0"`,
+		},
+		{
+			name:    "invalid action",
+			cfg:     "policy:\n  stability:\n    - kind: v0\n      action: ignore\n",
+			wantErr: `invalid value for policy.stability[0].action: "ignore" is not a valid action: must be one of "fail", "warn" or "allow"`,
+		},
+		{
+			name:    "invalid expiration",
+			cfg:     "policy:\n  stability:\n    - kind: v0\n      expires: next week\n",
+			wantErr: `invalid value for policy.stability[0].expires: "next week" is not a date in the format YYYY-MM-DD`,
+		},
+	} {
+		cfg, err := config.ReadConfig([]byte(tc.cfg))
+		require.NoError(t, err, "Case %d: %s", i, tc.name)
+		_, err = cfg.ToParams()
+		assert.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestToParamsInvalidGoVersionPolicy(t *testing.T) {
+	for i, tc := range []struct {
+		name    string
+		cfg     string
+		wantErr string
+	}{
+		{
+			name:    "invalid minimum",
+			cfg:     "policy:\n  go-version:\n    min: go1.21\n",
+			wantErr: `invalid value for policy.go-version: min "go1.21" is not a valid Go version: must be a version such as "1.22" or "1.22.5"`,
+		},
+		{
+			name:    "minimum newer than maximum",
+			cfg:     "policy:\n  go-version:\n    min: \"1.23\"\n    max: \"1.22\"\n",
+			wantErr: `invalid value for policy.go-version: min 1.23 is newer than max 1.22`,
+		},
+		{
+			name:    "negative maximum skew",
+			cfg:     "policy:\n  go-version:\n    align: true\n    max-skew: -1\n",
+			wantErr: `invalid value for policy.go-version: max-skew must be non-negative, was -1`,
+		},
+		{
+			name:    "maximum skew without alignment",
+			cfg:     "policy:\n  go-version:\n    max-skew: 1\n",
+			wantErr: `invalid value for policy.go-version: max-skew is only used if align is true`,
+		},
+	} {
+		cfg, err := config.ReadConfig([]byte(tc.cfg))
+		require.NoError(t, err, "Case %d: %s", i, tc.name)
+		_, err = cfg.ToParams()
+		assert.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestToParamsUpgrade(t *testing.T) {
+	cfg, err := config.ReadConfig([]byte("upgrade:\n  exclude:\n    - github.com/docker/...\n  build-check: true\n"))
+	require.NoError(t, err)
+
+	params, err := cfg.ToParams()
+	require.NoError(t, err)
+	assert.Equal(t, gomod.UpgradeParams{
+		Exclude:    []string{"github.com/docker/..."},
+		BuildCheck: true,
+	}, params.Upgrade)
+
+	cfg, err = config.ReadConfig([]byte("upgrade:\n  exclude:\n    - \"\"\n"))
+	require.NoError(t, err)
+	_, err = cfg.ToParams()
+	assert.EqualError(t, err, "invalid value for upgrade.exclude[0]: module pattern must not be empty")
+}
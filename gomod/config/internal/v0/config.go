@@ -0,0 +1,188 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package v0
+
+import (
+	"github.com/palantir/godel/v2/pkg/versionedconfig"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+type Config struct {
+	// Version of the configuration
+	versionedconfig.ConfigWithVersion `yaml:",inline,omitempty"`
+
+	// VendorMode specifies whether "go mod vendor" is run after "go mod tidy". Valid values are "auto", "always" and
+	// "never". If blank, "auto" is used.
+	VendorMode string `yaml:"vendor-mode,omitempty"`
+
+	// Concurrency is the maximum number of modules that are processed at the same time. If it is 0, the number of
+	// available CPUs is used.
+	Concurrency int `yaml:"concurrency,omitempty"`
+
+	// Tidy specifies the options used when running "go mod tidy".
+	Tidy TidyConfig `yaml:"tidy,omitempty"`
+
+	// Verify specifies which checks are performed when the task is run in verify mode.
+	Verify VerifyConfig `yaml:"verify,omitempty"`
+
+	// Workspace specifies the policy for go.work files.
+	Workspace WorkspaceConfig `yaml:"workspace,omitempty"`
+
+	// Toolchain specifies the Go toolchain used to run the go commands.
+	Toolchain ToolchainConfig `yaml:"toolchain,omitempty"`
+
+	// Policy specifies the policies that are enforced for the dependencies of every module in verify mode.
+	Policy PolicyConfig `yaml:"policy,omitempty"`
+
+	// Upgrade specifies the behavior of the mod-upgrade task.
+	Upgrade DependencyUpgradeConfig `yaml:"upgrade,omitempty"`
+}
+
+type TidyConfig struct {
+	// Compat is provided as the value of the "-compat" flag of "go mod tidy" if it is non-empty.
+	Compat string `yaml:"compat,omitempty"`
+
+	// IgnoreErrors specifies whether the "-e" flag is provided to "go mod tidy", which causes it to proceed despite
+	// errors encountered while loading packages.
+	IgnoreErrors bool `yaml:"ignore-errors,omitempty"`
+}
+
+type VerifyConfig struct {
+	// SkipGoSum specifies that verification should not fail if go.sum would be modified.
+	SkipGoSum bool `yaml:"skip-go-sum,omitempty"`
+
+	// SkipVendor specifies that verification should not fail if the vendor directory would be modified.
+	SkipVendor bool `yaml:"skip-vendor,omitempty"`
+}
+
+type WorkspaceConfig struct {
+	// ForbidGoWork specifies that verification should fail if a go.work file is committed to the repository.
+	ForbidGoWork bool `yaml:"forbid-go-work,omitempty"`
+}
+
+type ToolchainConfig struct {
+	// GoBinary is the path to the go binary used to run the go commands. If blank, "go" is resolved using PATH.
+	GoBinary string `yaml:"go-binary,omitempty"`
+
+	// GoToolchain is the value of the GOTOOLCHAIN environment variable used when running the go commands (for example,
+	// "go1.22.5" or "local"). If it names a specific toolchain, verification fails if the running toolchain is
+	// different. Verification always runs the go commands with GOTOOLCHAIN=local.
+	GoToolchain string `yaml:"go-toolchain,omitempty"`
+
+	// Directive is the toolchain name (such as "go1.22.5") written to the toolchain directive of the go.mod file of
+	// every module. The directive is removed if the go directive is at least as new, and verification fails if it is
+	// missing or out of date. If blank, the toolchain directive is not modified.
+	Directive string `yaml:"directive,omitempty"`
+}
+
+type PolicyConfig struct {
+	// Modules specifies the modules that may be part of the build list of every module.
+	Modules ModulePolicyConfig `yaml:"modules,omitempty"`
+
+	// Replace specifies the replace directives that are allowed in the go.mod file of every module.
+	Replace ReplacePolicyConfig `yaml:"replace,omitempty"`
+
+	// Stability are the rules for unstable versions (such as pseudo-versions) in the build list of every module. For
+	// every module and every kind of its version, the first matching rule that has not expired is applied.
+	Stability []StabilityRuleConfig `yaml:"stability,omitempty"`
+
+	// GoVersion specifies the range of values allowed for the go directive of every module and whether the go
+	// directives of the modules must be aligned.
+	GoVersion GoVersionPolicyConfig `yaml:"go-version,omitempty"`
+}
+
+type ModulePolicyConfig struct {
+	// Deny are the rules for the modules that may not be part of the build list.
+	Deny []ModuleRuleConfig `yaml:"deny,omitempty"`
+
+	// Allow are the rules for the modules that may be part of the build list. If any are specified, verification fails
+	// if the build list contains a module that does not match any of them.
+	Allow []ModuleRuleConfig `yaml:"allow,omitempty"`
+}
+
+type ModuleRuleConfig struct {
+	// Module is the pattern that matches the module path. "*" matches any sequence of characters other than "/" and a
+	// pattern that ends in "/..." also matches every module path under the rest of the pattern.
+	Module string `yaml:"module,omitempty"`
+
+	// Versions is the range of versions matched by the rule (for example, ">=v1.2.0, <v2.0.0"). If blank, all versions
+	// are matched.
+	Versions string `yaml:"versions,omitempty"`
+
+	// Reason describes the reason for the rule, which is included in verification failures.
+	Reason string `yaml:"reason,omitempty"`
+}
+
+type ReplacePolicyConfig struct {
+	// ForbidLocalPaths specifies that verification fails if a replace directive points to a directory that does not
+	// match any of AllowedLocalPaths.
+	ForbidLocalPaths bool `yaml:"forbid-local-paths,omitempty"`
+
+	// AllowedLocalPaths are the patterns for the directories (relative to the module directory, such as "../shared")
+	// that replace directives may point to. Directories outside of the project are never allowed.
+	AllowedLocalPaths []string `yaml:"allowed-local-paths,omitempty"`
+
+	// AllowedForks are the patterns for the modules that replace directives may point to. If any are specified,
+	// verification fails if a replace directive points to a different module that does not match any of them.
+	AllowedForks []string `yaml:"allowed-forks,omitempty"`
+}
+
+type StabilityRuleConfig struct {
+	// Kind is the kind of version matched by the rule. Valid values are "pseudo-version", "prerelease",
+	// "incompatible" and "v0".
+	Kind string `yaml:"kind,omitempty"`
+
+	// Module is the pattern that matches the module path. If blank, every module is matched.
+	Module string `yaml:"module,omitempty"`
+
+	// Action is the action taken for matching versions. Valid values are "fail", "warn" and "allow". If blank, "fail"
+	// is used.
+	Action string `yaml:"action,omitempty"`
+
+	// Expires is the date (in the format "2006-01-02") until which the rule is applied, which is used for temporary
+	// exceptions. If blank, the rule never expires.
+	Expires string `yaml:"expires,omitempty"`
+
+	// Reason describes the reason for the rule, which is included in verification failures.
+	Reason string `yaml:"reason,omitempty"`
+}
+
+type GoVersionPolicyConfig struct {
+	// Min is the minimum allowed value of the go directive (such as "1.21"). If blank, there is no minimum.
+	Min string `yaml:"min,omitempty"`
+
+	// Max is the maximum allowed value of the go directive (such as "1.22"). Only the components that are specified are
+	// compared, so "1.22" allows "1.22.5". If blank, there is no maximum.
+	Max string `yaml:"max,omitempty"`
+
+	// Align specifies that verification fails if the go directives of the modules in the project do not agree (or
+	// differ by more than MaxSkew language versions).
+	Align bool `yaml:"align,omitempty"`
+
+	// MaxSkew is the number of language versions (such as "1.21" and "1.22") by which the go directives of the modules
+	// may differ if Align is true. If 0, the go directives must be identical.
+	MaxSkew int `yaml:"max-skew,omitempty"`
+}
+
+type DependencyUpgradeConfig struct {
+	// Exclude are the patterns for the modules that are never upgraded directly by the mod-upgrade task.
+	Exclude []string `yaml:"exclude,omitempty"`
+
+	// BuildCheck specifies that "go build ./..." is run for every module after upgrading. If it fails, the go.mod,
+	// go.sum and vendor paths of every module are restored.
+	BuildCheck bool `yaml:"build-check,omitempty"`
+}
+
+func UpgradeConfig(cfgBytes []byte) ([]byte, error) {
+	if err := validateKeys(cfgBytes, Config{}); err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := yaml.UnmarshalStrict(cfgBytes, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal mod-plugin v0 configuration")
+	}
+	return cfgBytes, nil
+}
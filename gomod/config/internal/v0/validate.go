@@ -0,0 +1,125 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package v0
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+var yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+// validateKeys returns an error if the provided YAML contains a key that does not correspond to a field of the type of
+// cfg. The error contains the full path to the unknown key and the keys that are valid at that location. Type
+// mismatches are not reported: they are handled when the YAML is unmarshaled.
+func validateKeys(cfgBytes []byte, cfg any) error {
+	var node any
+	if err := yaml.Unmarshal(cfgBytes, &node); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal mod-plugin configuration as YAML")
+	}
+	return validateNodeKeys("", node, reflect.TypeOf(cfg))
+}
+
+func validateNodeKeys(keyPath string, node any, typ reflect.Type) error {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if reflect.PointerTo(typ).Implements(yamlUnmarshalerType) {
+		// types with custom unmarshal logic are responsible for their own validation
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		nodeMap, ok := node.(map[any]any)
+		if !ok {
+			return nil
+		}
+		fields := yamlFields(typ)
+		for _, k := range sortedKeys(nodeMap) {
+			fieldType, ok := fields[k]
+			if !ok {
+				return errors.Errorf("unknown key %q in mod-plugin configuration: valid keys%s are %s",
+					joinKeyPath(keyPath, k), keyPathLocation(keyPath), strings.Join(sortedKeys(fields), ", "))
+			}
+			if err := validateNodeKeys(joinKeyPath(keyPath, k), nodeMap[k], fieldType); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := node.([]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			if err := validateNodeKeys(fmt.Sprintf("%s[%d]", keyPath, i), item, typ.Elem()); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		nodeMap, ok := node.(map[any]any)
+		if !ok {
+			return nil
+		}
+		for _, k := range sortedKeys(nodeMap) {
+			if err := validateNodeKeys(joinKeyPath(keyPath, k), nodeMap[k], typ.Elem()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// yamlFields returns a map from the YAML key of each field of the provided struct type to the type of the field.
+// Fields of inline structs are included in the returned map.
+func yamlFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(","+opts+",", ",inline,") {
+			for k, v := range yamlFields(field.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+func sortedKeys[V any, K comparable](m map[K]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, fmt.Sprint(k))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinKeyPath(keyPath, key string) string {
+	if keyPath == "" {
+		return key
+	}
+	return keyPath + "." + key
+}
+
+func keyPathLocation(keyPath string) string {
+	if keyPath == "" {
+		return ""
+	}
+	return fmt.Sprintf(" for %q", keyPath)
+}
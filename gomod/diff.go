@@ -0,0 +1,200 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines included before and after each change in a unified diff.
+const diffContextLines = 3
+
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// unifiedDiff returns the unified diff between the provided contents using "a/<name>" and "b/<name>" as the file
+// labels so that the output can be applied using "git apply". Content that does not end with a newline is marked with
+// "\ No newline at end of file". Returns the empty string if the contents are equal.
+func unifiedDiff(name string, before, after []byte) string {
+	if string(before) == string(after) {
+		return ""
+	}
+	lines := diffLines(splitLines(string(before)), splitLines(string(after)))
+
+	out := &strings.Builder{}
+	_, _ = fmt.Fprintf(out, "--- a/%s\n+++ b/%s\n", name, name)
+
+	// aLine and bLine track the 0-based line number in the before and after contents of lines[i]
+	aLine, bLine := 0, 0
+	for i := 0; i < len(lines); {
+		if lines[i].op == diffEqual {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+
+		// start of a hunk: include up to diffContextLines of preceding context. The preceding hunk (if any) ended at
+		// least 2*diffContextLines+1 equal lines ago, so the context never overlaps with it.
+		start := max(i-diffContextLines, 0)
+		// extend the hunk until a run of more than 2*diffContextLines equal lines is found
+		end := i
+		for end < len(lines) {
+			if lines[end].op != diffEqual {
+				end++
+				continue
+			}
+			run := 0
+			for end+run < len(lines) && lines[end+run].op == diffEqual {
+				run++
+			}
+			if end+run == len(lines) || run > 2*diffContextLines {
+				end += min(run, diffContextLines)
+				break
+			}
+			end += run
+		}
+
+		hunkAStart, hunkBStart := aLine-(i-start), bLine-(i-start)
+		aCount, bCount := 0, 0
+		hunk := &strings.Builder{}
+		for _, line := range lines[start:end] {
+			prefix := " "
+			switch line.op {
+			case diffEqual:
+				aCount++
+				bCount++
+			case diffDelete:
+				aCount++
+				prefix = "-"
+			case diffInsert:
+				bCount++
+				prefix = "+"
+			}
+			hunk.WriteString(prefix + line.text)
+			if !strings.HasSuffix(line.text, "\n") {
+				hunk.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		_, _ = fmt.Fprintf(out, "@@ -%s +%s @@\n%s", hunkRange(hunkAStart, aCount), hunkRange(hunkBStart, bCount), hunk.String())
+
+		aLine, bLine = hunkAStart+aCount, hunkBStart+bCount
+		i = end
+	}
+	return out.String()
+}
+
+// hunkRange returns the range for a unified diff hunk header given the 0-based start line and line count.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// splitLines splits s into lines that retain their trailing newline so that a final line without a newline differs
+// from the same line with one.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit script that transforms a into b computed using the Myers diff algorithm.
+func diffLines(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	maxD := n + m
+
+	// v[k] stores the furthest x reached on diagonal k. trace[d] stores the values of v for diagonals -(d+1)..(d+1)
+	// before round d so that the path can be reconstructed.
+	v := make(map[int]int, 2*maxD+2)
+	var trace [][]int
+	for d := 0; d <= maxD; d++ {
+		snapshot := make([]int, 2*d+3)
+		for k := -(d + 1); k <= d+1; k++ {
+			snapshot[k+d+1] = v[k]
+		}
+		trace = append(trace, snapshot)
+
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1] < v[k+1]) {
+				x = v[k+1]
+			} else {
+				x = v[k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	var reversed []diffLine
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		vd := func(k int) int {
+			return trace[d][k+d+1]
+		}
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && vd(k-1) < vd(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := vd(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			reversed = append(reversed, diffLine{op: diffEqual, text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, diffLine{op: diffInsert, text: b[y-1]})
+			} else {
+				reversed = append(reversed, diffLine{op: diffDelete, text: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	lines := make([]diffLine, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+	return lines
+}
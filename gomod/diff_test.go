@@ -0,0 +1,126 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	for i, tc := range []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name:   "equal content",
+			before: "module foo\n",
+			after:  "module foo\n",
+			want:   "",
+		},
+		{
+			name: "added require block",
+			before: `module github.com/mod/test
+
+go 1.22
+`,
+			after: `module github.com/mod/test
+
+go 1.22
+
+require github.com/pkg/errors v0.9.1
+`,
+			want: "--- a/go.mod\n" +
+				"+++ b/go.mod\n" +
+				"@@ -1,3 +1,5 @@\n" +
+				" module github.com/mod/test\n" +
+				" \n" +
+				" go 1.22\n" +
+				"+\n" +
+				"+require github.com/pkg/errors v0.9.1\n",
+		},
+		{
+			name:   "content added to empty file",
+			before: "",
+			after:  "github.com/pkg/errors v0.9.1 h1:abc=\n",
+			want: `--- a/go.mod
++++ b/go.mod
+@@ -0,0 +1 @@
++github.com/pkg/errors v0.9.1 h1:abc=
+`,
+		},
+		{
+			name:   "separate hunks for distant changes",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			after:  "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			want: `--- a/go.mod
++++ b/go.mod
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -9,4 +9,4 @@
+ 9
+ 10
+ 11
+-12
++twelve
+`,
+		},
+		{
+			name:   "single hunk for nearby changes",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n",
+			after:  "1\ntwo\n3\n4\n5\n6\nseven\n8\n",
+			want: `--- a/go.mod
++++ b/go.mod
+@@ -1,8 +1,8 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+ 6
+-7
++seven
+ 8
+`,
+		},
+		{
+			name:   "trailing newline removed",
+			before: "module github.com/mod/test\n",
+			after:  "module github.com/mod/test",
+			want: `--- a/go.mod
++++ b/go.mod
+@@ -1 +1 @@
+-module github.com/mod/test
++module github.com/mod/test
+\ No newline at end of file
+`,
+		},
+		{
+			name:   "trailing newline added",
+			before: "module github.com/mod/test\n\ngo 1.22",
+			after:  "module github.com/mod/test\n\ngo 1.22\n",
+			want: `--- a/go.mod
++++ b/go.mod
+@@ -1,3 +1,3 @@
+ module github.com/mod/test
+ 
+-go 1.22
+\ No newline at end of file
++go 1.22
+`,
+		},
+	} {
+		got := unifiedDiff("go.mod", []byte(tc.before), []byte(tc.after))
+		assert.Equal(t, tc.want, got, "Case %d: %s", i, tc.name)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CommandError is the error returned when a command run by the task exits with a non-zero exit code.
+type CommandError struct {
+	// Args are the command-line arguments of the command, starting with the command name.
+	Args []string
+	// Dir is the working directory of the command.
+	Dir string
+	// ExitCode is the exit code of the command.
+	ExitCode int
+	// Stderr is the output that the command wrote to stderr.
+	Stderr string
+}
+
+// Error returns a one-line summary of the failure followed by the output that the command wrote to stderr.
+func (e *CommandError) Error() string {
+	summary := fmt.Sprintf("%q failed with exit code %d in %s", strings.Join(e.Args, " "), e.ExitCode, e.Dir)
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		return summary + ":\n" + stderr
+	}
+	return summary
+}
+
+// ModuleError is the error returned when the task fails for a specific module.
+type ModuleError struct {
+	// Module is the module path of the module.
+	Module string
+	// Err is the error that occurred.
+	Err error
+}
+
+func (e *ModuleError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Module, e.Err)
+}
+
+func (e *ModuleError) Unwrap() error {
+	return e.Err
+}
+
+// multiError combines multiple errors into one. Its message is the messages of the errors separated by newlines.
+type multiError []error
+
+func (e multiError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e multiError) Unwrap() []error {
+	return e
+}
+
+// joinErrors returns nil if errs is empty, the only error if errs has one element, and a multiError otherwise.
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return multiError(errs)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunReturnsCommandError(t *testing.T) {
+	dir := t.TempDir()
+	err := run(context.Background(), GoExecutor{}, dir, nil, &bytes.Buffer{}, "mod", "unknown-subcommand")
+	require.Error(t, err)
+
+	var cmdErr *CommandError
+	require.True(t, errors.As(err, &cmdErr), "unexpected error type %T", err)
+	assert.Equal(t, []string{"go", "mod", "unknown-subcommand"}, cmdErr.Args)
+	assert.Equal(t, dir, cmdErr.Dir)
+	assert.NotZero(t, cmdErr.ExitCode)
+	assert.Contains(t, cmdErr.Stderr, "unknown command")
+	assert.Regexp(t, `^"go mod unknown-subcommand" failed with exit code \d+ in .+:\n`, cmdErr.Error())
+}
+
+func TestJoinErrorsUnwraps(t *testing.T) {
+	cmdErr := &CommandError{Args: []string{"go", "mod", "tidy"}, Dir: "/project/tools", ExitCode: 1}
+	err := joinErrors([]error{
+		&ModuleError{Module: "github.com/mod/test", Err: errors.New("failed")},
+		&ModuleError{Module: "github.com/mod/test/tools", Err: cmdErr},
+	})
+	assert.EqualError(t, err, "github.com/mod/test: failed\n"+`github.com/mod/test/tools: "go mod tidy" failed with exit code 1 in /project/tools`)
+
+	var gotErr *CommandError
+	require.True(t, errors.As(err, &gotErr))
+	assert.Equal(t, cmdErr, gotErr)
+}
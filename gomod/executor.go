@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// Command is an invocation of the go command.
+type Command struct {
+	// Dir is the directory in which the command is run.
+	Dir string
+	// Args are the arguments provided to the go command, not including the name of the binary itself (for example,
+	// []string{"mod", "tidy"}).
+	Args []string
+	// Env contains environment variables of the form "KEY=value" that are set for the command in addition to those of
+	// the current process.
+	Env []string
+	// Stdout and Stderr are the writers to which the standard output and standard error of the command are written.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Executor runs go commands. All of the go commands run by this package are run using an Executor, which allows the
+// toolchain to be replaced (for example, by a pinned go binary, a sandbox or a fake for tests).
+//
+// If the command runs but exits with a non-zero exit code, Run should return an error that implements
+// "ExitCode() int" (such as *exec.ExitError) so that the failure can be reported as a *CommandError. Run should return
+// once the provided context is done.
+type Executor interface {
+	Run(ctx context.Context, cmd Command) error
+}
+
+// GoExecutor is an Executor that runs the go binary as a subprocess.
+type GoExecutor struct {
+	// GoBinary is the path to the go binary. If it is empty, "go" is resolved using PATH.
+	GoBinary string
+}
+
+func (e GoExecutor) Run(ctx context.Context, cmd Command) error {
+	goBinary := e.GoBinary
+	if goBinary == "" {
+		goBinary = "go"
+	}
+	execCmd := exec.CommandContext(ctx, goBinary, cmd.Args...)
+	execCmd.Dir = cmd.Dir
+	execCmd.Env = append(os.Environ(), cmd.Env...)
+	execCmd.Stdout = cmd.Stdout
+	execCmd.Stderr = cmd.Stderr
+	return execCmd.Run()
+}
+
+// exitCoder is implemented by errors that report the exit code of a command that ran and failed.
+type exitCoder interface {
+	ExitCode() int
+}
@@ -0,0 +1,361 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testGoMod       = "module github.com/mod/test\n\ngo 1.22\n\nrequire github.com/pkg/errors v0.9.1\n"
+	testGoSum       = "github.com/pkg/errors v0.9.1 h1:abc=\ngithub.com/pkg/errors v0.9.1/go.mod h1:def=\n"
+	testModulesTxt  = "# github.com/pkg/errors v0.9.1\n## explicit\ngithub.com/pkg/errors\n"
+	testVendoredSrc = "package errors\n"
+	testBuildList   = `{"Path": "github.com/mod/test", "Main": true}
+{"Path": "github.com/pkg/errors", "Version": "v0.9.1"}
+`
+	testModWhy = "# github.com/pkg/errors\ngithub.com/mod/test\ngithub.com/pkg/errors\n"
+)
+
+// fakeToolchain is an Executor that simulates the go commands run by this package. "go mod tidy" writes tidyGoMod and
+// tidyGoSum to the files specified by "-modfile", "go mod vendor" and "go work vendor" write vendorFiles to the
+// directory specified by "-o", "go work sync" writes workSum to the go.work.sum file next to the go.work file specified
+// by GOWORK (if workSum is non-empty), "go list", "go mod why" and "go mod graph" print listOutput, whyOutput and
+// graphOutput and "go get" and "go build" do nothing.
+type fakeToolchain struct {
+	goFlags     string
+	goVersion   string
+	tidyGoMod   string
+	tidyGoSum   string
+	vendorFiles map[string]string
+	listOutput  string
+	whyOutput   string
+	graphOutput string
+	workSum     string
+	// failArgs causes commands whose arguments start with the provided value to fail with exit code 1.
+	failArgs string
+
+	mu       sync.Mutex
+	commands []string
+	envs     [][]string
+}
+
+type fakeExitError int
+
+func (e fakeExitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+func (e fakeExitError) ExitCode() int {
+	return int(e)
+}
+
+func (f *fakeToolchain) Run(ctx context.Context, cmd Command) error {
+	f.mu.Lock()
+	f.commands = append(f.commands, strings.Join(cmd.Args, " "))
+	f.envs = append(f.envs, cmd.Env)
+	f.mu.Unlock()
+
+	if f.failArgs != "" && strings.HasPrefix(strings.Join(cmd.Args, " "), f.failArgs) {
+		_, _ = fmt.Fprintln(cmd.Stderr, "go: simulated failure")
+		return fakeExitError(1)
+	}
+	flags := make(map[string]string)
+	for i, arg := range cmd.Args {
+		if k, v, ok := strings.Cut(arg, "="); ok {
+			flags[k] = v
+		} else if arg == "-o" && i+1 < len(cmd.Args) {
+			flags["-o"] = cmd.Args[i+1]
+		}
+	}
+	switch {
+	case strings.HasPrefix(strings.Join(cmd.Args, " "), "env -json"):
+		return json.NewEncoder(cmd.Stdout).Encode(map[string]string{"GOFLAGS": f.goFlags, "GOVERSION": f.goVersion})
+	case strings.HasPrefix(strings.Join(cmd.Args, " "), "mod tidy"):
+		modFile := flags["-modfile"]
+		if err := os.WriteFile(modFile, []byte(f.tidyGoMod), 0644); err != nil {
+			return err
+		}
+		return os.WriteFile(strings.TrimSuffix(modFile, ".mod")+".sum", []byte(f.tidyGoSum), 0644)
+	case strings.HasPrefix(strings.Join(cmd.Args, " "), "work sync"):
+		if f.workSum == "" {
+			return nil
+		}
+		for _, kv := range cmd.Env {
+			if goWork, ok := strings.CutPrefix(kv, "GOWORK="); ok {
+				return os.WriteFile(goWork+".sum", []byte(f.workSum), 0644)
+			}
+		}
+		return errors.Errorf("GOWORK is not set for %v", cmd.Args)
+	case strings.HasPrefix(strings.Join(cmd.Args, " "), "mod vendor"), strings.HasPrefix(strings.Join(cmd.Args, " "), "work vendor"):
+		writeTestFiles(flags["-o"], f.vendorFiles)
+		return nil
+	case strings.HasPrefix(strings.Join(cmd.Args, " "), "list "):
+		_, err := fmt.Fprint(cmd.Stdout, f.listOutput)
+		return err
+	case strings.HasPrefix(strings.Join(cmd.Args, " "), "mod why"):
+		_, err := fmt.Fprint(cmd.Stdout, f.whyOutput)
+		return err
+	case strings.HasPrefix(strings.Join(cmd.Args, " "), "mod graph"):
+		_, err := fmt.Fprint(cmd.Stdout, f.graphOutput)
+		return err
+	case strings.HasPrefix(strings.Join(cmd.Args, " "), "get "), strings.HasPrefix(strings.Join(cmd.Args, " "), "build "):
+		return nil
+	}
+	return errors.Errorf("unexpected command %v", cmd.Args)
+}
+
+func (f *fakeToolchain) ranCommand(prefix string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, cmd := range f.commands {
+		if strings.HasPrefix(cmd, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// allCommandsHaveEnv returns true if every command was run with the provided environment variable.
+func (f *fakeToolchain) allCommandsHaveEnv(kv string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, env := range f.envs {
+		if !slices.Contains(env, kv) {
+			return false
+		}
+	}
+	return len(f.envs) > 0
+}
+
+func writeTestFiles(dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		_ = os.WriteFile(path, []byte(content), 0644)
+	}
+}
+
+func newTestModule(t *testing.T, vendored bool) string {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": testGoMod,
+		"go.sum": testGoSum,
+	}
+	if vendored {
+		files["vendor/modules.txt"] = testModulesTxt
+		files["vendor/github.com/pkg/errors/errors.go"] = testVendoredSrc
+	}
+	writeTestFiles(projectDir, files)
+	return projectDir
+}
+
+func newUpToDateToolchain() *fakeToolchain {
+	return &fakeToolchain{
+		tidyGoMod: testGoMod,
+		tidyGoSum: testGoSum,
+		vendorFiles: map[string]string{
+			"modules.txt":                     testModulesTxt,
+			"github.com/pkg/errors/errors.go": testVendoredSrc,
+		},
+		listOutput: testBuildList,
+		whyOutput:  testModWhy,
+	}
+}
+
+func TestRunVerifyWithFakeToolchain(t *testing.T) {
+	for i, tc := range []struct {
+		name       string
+		vendored   bool
+		toolchain  func(*fakeToolchain)
+		params     Params
+		wantErr    string
+		wantVendor bool
+	}{
+		{
+			name:       "up-to-date module with vendor directory",
+			vendored:   true,
+			wantVendor: true,
+		},
+		{
+			name: "up-to-date module without vendor directory",
+		},
+		{
+			name:     "go.mod modified",
+			vendored: true,
+			toolchain: func(f *fakeToolchain) {
+				f.tidyGoMod = "module github.com/mod/test\n\ngo 1.22\n"
+			},
+			wantErr: "go.mod modified:\n--- a/go.mod\n+++ b/go.mod\n@@ -1,5 +1,3 @@\n module github.com/mod/test\n \n go 1.22\n-\n-require github.com/pkg/errors v0.9.1",
+		},
+		{
+			name: "go.sum modified",
+			toolchain: func(f *fakeToolchain) {
+				f.tidyGoSum = "github.com/pkg/errors v0.9.1/go.mod h1:def=\n"
+			},
+			wantErr: "go.sum modified:\n--- a/go.sum\n+++ b/go.sum\n@@ -1,2 +1 @@\n-github.com/pkg/errors v0.9.1 h1:abc=\n github.com/pkg/errors v0.9.1/go.mod h1:def=",
+		},
+		{
+			name: "go.sum modification ignored",
+			toolchain: func(f *fakeToolchain) {
+				f.tidyGoSum = "github.com/pkg/errors v0.9.1/go.mod h1:def=\n"
+			},
+			params: Params{Verify: VerifyParams{SkipGoSum: true}},
+		},
+		{
+			name:     "vendor directory modified",
+			vendored: true,
+			toolchain: func(f *fakeToolchain) {
+				f.vendorFiles["github.com/pkg/errors/errors.go"] = "package errors // modified\n"
+			},
+			wantErr:    "vendor directory modified:\n",
+			wantVendor: true,
+		},
+		{
+			name:     "vendor directory modification ignored",
+			vendored: true,
+			toolchain: func(f *fakeToolchain) {
+				f.vendorFiles["github.com/pkg/errors/errors.go"] = "package errors // modified\n"
+			},
+			params: Params{Verify: VerifyParams{SkipVendor: true}},
+		},
+		{
+			name:     "vendoring disabled by GOFLAGS",
+			vendored: true,
+			toolchain: func(f *fakeToolchain) {
+				f.goFlags = "-mod=mod"
+			},
+		},
+		{
+			name: "vendor directory would be created",
+			toolchain: func(f *fakeToolchain) {
+				f.goFlags = "-mod=vendor"
+			},
+			wantErr:    "vendor directory does not exist but would be created by vendoring",
+			wantVendor: true,
+		},
+		{
+			name:   "module allowed by policy",
+			params: Params{Policy: PolicyParams{Modules: ModulePolicyParams{Allow: []ModuleRule{{Module: "github.com/pkg/..."}}}}},
+		},
+		{
+			name: "module denied by policy",
+			params: Params{Policy: PolicyParams{Modules: ModulePolicyParams{Deny: []ModuleRule{
+				{Module: "github.com/pkg/errors", Versions: VersionRange{{Op: "<", Version: "v1.0.0"}}, Reason: "use the standard library"},
+			}}}},
+			wantErr: "go.mod violates the module policy:\n" +
+				"github.com/pkg/errors@v0.9.1 is denied by the rule \"github.com/pkg/errors <v1.0.0\": use the standard library\n" +
+				"    go.mod:5: require github.com/pkg/errors v0.9.1\n" +
+				"    import chain: github.com/mod/test -> github.com/pkg/errors",
+		},
+		{
+			name:    "toolchain directive missing",
+			params:  Params{Toolchain: ToolchainParams{Directive: "go1.22.5"}},
+			wantErr: "go.mod: the toolchain directive is missing, expected toolchain go1.22.5",
+		},
+		{
+			name:   "toolchain directive redundant with go directive",
+			params: Params{Toolchain: ToolchainParams{Directive: "go1.22"}},
+		},
+		{
+			name:       "vendoring forced by vendor-mode",
+			params:     Params{VendorMode: VendorModeAlways},
+			wantErr:    "vendor directory does not exist but would be created by vendoring",
+			wantVendor: true,
+		},
+	} {
+		projectDir := newTestModule(t, tc.vendored)
+		toolchain := newUpToDateToolchain()
+		if tc.toolchain != nil {
+			tc.toolchain(toolchain)
+		}
+		params := tc.params
+		params.Executor = toolchain
+
+		err := Run(context.Background(), projectDir, true, params, &bytes.Buffer{})
+		if tc.wantErr == "" {
+			assert.NoError(t, err, "Case %d: %s", i, tc.name)
+		} else {
+			require.Error(t, err, "Case %d: %s", i, tc.name)
+			assert.Contains(t, err.Error(), tc.wantErr, "Case %d: %s", i, tc.name)
+		}
+		assert.Equal(t, tc.wantVendor, toolchain.ranCommand("mod vendor"), "Case %d: %s", i, tc.name)
+
+		// verification never modifies the module
+		goMod, err := os.ReadFile(filepath.Join(projectDir, "go.mod"))
+		require.NoError(t, err, "Case %d: %s", i, tc.name)
+		assert.Equal(t, testGoMod, string(goMod), "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestRunApplyWithFakeToolchain(t *testing.T) {
+	projectDir := newTestModule(t, true)
+	toolchain := newUpToDateToolchain()
+	toolchain.tidyGoMod = "module github.com/mod/test\n\ngo 1.22\n\nrequire github.com/pkg/errors v0.9.2\n"
+	toolchain.vendorFiles = map[string]string{
+		"modules.txt":                      "# github.com/pkg/errors v0.9.2\n## explicit\ngithub.com/pkg/errors\n",
+		"github.com/pkg/errors/errors.go":  testVendoredSrc,
+		"github.com/pkg/errors/stack.go":   "package errors\n",
+		"github.com/pkg/errors/go113.go":   "package errors\n",
+		"github.com/pkg/errors/.gitignore": "",
+	}
+
+	err := Run(context.Background(), projectDir, false, Params{Executor: toolchain}, &bytes.Buffer{})
+	require.NoError(t, err)
+
+	goMod, err := os.ReadFile(filepath.Join(projectDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, toolchain.tidyGoMod, string(goMod))
+	modulesTxt, err := os.ReadFile(filepath.Join(projectDir, "vendor", "modules.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, toolchain.vendorFiles["modules.txt"], string(modulesTxt))
+	_, err = os.Stat(filepath.Join(projectDir, "vendor", "github.com", "pkg", "errors", "stack.go"))
+	assert.NoError(t, err)
+
+	// temporary directories are removed
+	entries, err := os.ReadDir(projectDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"go.mod", "go.sum", "vendor"}, names)
+}
+
+func TestRunApplyCommandFailureDoesNotModifyModule(t *testing.T) {
+	projectDir := newTestModule(t, true)
+	toolchain := newUpToDateToolchain()
+	toolchain.tidyGoMod = "module github.com/mod/test\n\ngo 1.22\n"
+	toolchain.failArgs = "mod vendor"
+
+	err := Run(context.Background(), projectDir, false, Params{Executor: toolchain}, &bytes.Buffer{})
+	require.Error(t, err)
+
+	var modErr *ModuleError
+	require.True(t, errors.As(err, &modErr), "unexpected error type %T", err)
+	assert.Equal(t, "github.com/mod/test", modErr.Module)
+	var cmdErr *CommandError
+	require.True(t, errors.As(err, &cmdErr), "unexpected error type %T", err)
+	assert.Equal(t, 1, cmdErr.ExitCode)
+	assert.Equal(t, "go: simulated failure\n", cmdErr.Stderr)
+	assert.Equal(t, projectDir, cmdErr.Dir)
+
+	goMod, err := os.ReadFile(filepath.Join(projectDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, testGoMod, string(goMod))
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"fmt"
+	"go/version"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+)
+
+// GoVersionPolicyParams specifies the range of values allowed for the go directive of every module and whether the go
+// directives of the modules in the project must be aligned.
+type GoVersionPolicyParams struct {
+	// Min is the minimum allowed value (such as "1.21"). If empty, there is no minimum.
+	Min string
+	// Max is the maximum allowed value (such as "1.22" or "1.22.5"). If empty, there is no maximum. Only the components
+	// specified by the maximum are compared, so "1.22" allows "1.22.5" but not "1.23.0".
+	Max string
+	// Align specifies that the go directives of all of the modules in the project must agree (or differ by at most
+	// MaxSkew language versions).
+	Align bool
+	// MaxSkew is the number of language versions by which the go directives of the modules may differ if Align is
+	// true: for example, 1 allows "1.21.3" and "1.22.0" but not "1.20" and "1.22". If 0, the go directives must be
+	// identical.
+	MaxSkew int
+}
+
+// enabled returns true if the policy restricts the go directive of every module.
+func (p GoVersionPolicyParams) enabled() bool {
+	return p.Min != "" || p.Max != ""
+}
+
+// Validate returns an error if the minimum or maximum is not a valid Go version, if the minimum is newer than the
+// maximum or if the maximum skew is negative or specified without alignment.
+func (p GoVersionPolicyParams) Validate() error {
+	if p.MaxSkew < 0 {
+		return errors.Errorf("max-skew must be non-negative, was %d", p.MaxSkew)
+	}
+	if p.MaxSkew > 0 && !p.Align {
+		return errors.Errorf("max-skew is only used if align is true")
+	}
+	for _, bound := range []struct {
+		name    string
+		version string
+	}{{"min", p.Min}, {"max", p.Max}} {
+		if bound.version == "" {
+			continue
+		}
+		if !modfile.GoVersionRE.MatchString(bound.version) {
+			return errors.Errorf("%s %q is not a valid Go version: must be a version such as \"1.22\" or \"1.22.5\"", bound.name, bound.version)
+		}
+	}
+	if p.Min != "" && p.Max != "" && p.exceedsMax(p.Min) {
+		return errors.Errorf("min %s is newer than max %s", p.Min, p.Max)
+	}
+	return nil
+}
+
+// goVersionViolation returns the reason why the go directive of a module (before and after running "go mod tidy")
+// violates the policy or the empty string if it does not. If the go directive after running "go mod tidy" is newer
+// than the maximum, the dependencies in the provided build list that require a newer version are listed.
+func goVersionViolation(policy GoVersionPolicyParams, before, after string, buildList []buildListModule) string {
+	if after == "" {
+		return ""
+	}
+	var directive string
+	switch before {
+	case after:
+		directive = fmt.Sprintf("the go directive is %s", after)
+	case "":
+		directive = fmt.Sprintf("go mod tidy would add the go directive %s", after)
+	default:
+		directive = fmt.Sprintf("go mod tidy would change the go directive from %s to %s", before, after)
+	}
+	switch {
+	case policy.Min != "" && version.Compare("go"+after, "go"+policy.Min) < 0:
+		return fmt.Sprintf("%s, which is older than the minimum allowed version %s", directive, policy.Min)
+	case policy.Max != "" && policy.exceedsMax(after):
+		violation := fmt.Sprintf("%s, which is newer than the maximum allowed version %s", directive, policy.Max)
+		if deps := goVersionDependencies(buildList, policy); len(deps) > 0 {
+			violation += "\n" + strings.Join(deps, "\n")
+		}
+		return violation
+	}
+	return ""
+}
+
+// goVersionDependencies returns a description of every dependency in the provided build list whose own go.mod file
+// requires a Go version newer than the maximum of the policy, with the dependencies that require the newest versions
+// first.
+func goVersionDependencies(buildList []buildListModule, policy GoVersionPolicyParams) []string {
+	var deps []buildListModule
+	for _, mod := range buildList {
+		if !mod.Main && mod.GoVersion != "" && policy.exceedsMax(mod.GoVersion) {
+			deps = append(deps, mod)
+		}
+	}
+	sort.SliceStable(deps, func(i, j int) bool {
+		return version.Compare("go"+deps[i].GoVersion, "go"+deps[j].GoVersion) > 0
+	})
+	var descriptions []string
+	for _, dep := range deps {
+		descriptions = append(descriptions, fmt.Sprintf("    %s requires go %s", dep, dep.GoVersion))
+	}
+	return descriptions
+}
+
+// exceedsMax returns true if the Go version v (such as "1.22.5") is newer than the maximum of the policy. If the
+// maximum is a language version (such as "1.22"), only the language version of v is compared, so "1.22.5" does not
+// exceed "1.22".
+func (p GoVersionPolicyParams) exceedsMax(v string) bool {
+	max := "go" + p.Max
+	if version.Lang(max) == max {
+		return version.Compare(version.Lang("go"+v), max) > 0
+	}
+	return version.Compare("go"+v, max) > 0
+}
+
+// addGoVersionPolicyCheck records the result of checking the go directive of the module before and after running
+// "go mod tidy" (goModBefore and goModAfter) against the policy in report. buildList returns the build list of the
+// module, which is only called if the go directive is newer than the maximum.
+func addGoVersionPolicyCheck(mod module, goModBefore, goModAfter []byte, policy GoVersionPolicyParams, buildList func() ([]buildListModule, error), report *ModuleReport) error {
+	before, err := parseGoDirective(mod.relPath("go.mod"), goModBefore)
+	if err != nil {
+		return err
+	}
+	after, err := parseGoDirective(mod.relPath("go.mod"), goModAfter)
+	if err != nil {
+		return err
+	}
+	var modules []buildListModule
+	if policy.Max != "" && after != "" && policy.exceedsMax(after) {
+		if modules, err = buildList(); err != nil {
+			return err
+		}
+	}
+	var failure string
+	if violation := goVersionViolation(policy, before, after, modules); violation != "" {
+		failure = fmt.Sprintf("%s: %s", mod.relPath("go.mod"), violation)
+	}
+	report.addCheck(checkGoVersionPolicy, failure)
+	return nil
+}
+
+// goVersionAlignmentFailure returns the verification failure for the go directives of the provided modules (after
+// running "go mod tidy") if they differ by more than the maximum skew of the policy. Returns the empty string if they
+// are aligned. Modules without a go directive are ignored.
+func goVersionAlignmentFailure(policy GoVersionPolicyParams, modules []ModuleReport) string {
+	var withDirective []ModuleReport
+	for _, mod := range modules {
+		if mod.GoVersion != "" {
+			withDirective = append(withDirective, mod)
+		}
+	}
+	if len(withDirective) < 2 {
+		return ""
+	}
+	oldest, newest := withDirective[0].GoVersion, withDirective[0].GoVersion
+	for _, mod := range withDirective[1:] {
+		if version.Compare("go"+mod.GoVersion, "go"+oldest) < 0 {
+			oldest = mod.GoVersion
+		}
+		if version.Compare("go"+mod.GoVersion, "go"+newest) > 0 {
+			newest = mod.GoVersion
+		}
+	}
+
+	var failure string
+	switch {
+	case policy.MaxSkew == 0 && oldest != newest:
+		failure = "the go directives of the modules do not agree:"
+	case policy.MaxSkew > 0 && goMinorVersion(newest)-goMinorVersion(oldest) > policy.MaxSkew:
+		failure = fmt.Sprintf("the language versions of the go directives of the modules differ by more than %d:", policy.MaxSkew)
+	default:
+		return ""
+	}
+	// list the modules with the newest go directives first
+	sort.SliceStable(withDirective, func(i, j int) bool {
+		return version.Compare("go"+withDirective[i].GoVersion, "go"+withDirective[j].GoVersion) > 0
+	})
+	for _, mod := range withDirective {
+		failure += fmt.Sprintf("\n    %s: go %s", path.Join(mod.Dir, "go.mod"), mod.GoVersion)
+	}
+	return failure
+}
+
+// goMinorVersion returns the minor version of the provided Go version: for example, 22 for "1.22.5".
+func goMinorVersion(v string) int {
+	_, minor, _ := strings.Cut(strings.TrimPrefix(version.Lang("go"+v), "go"), ".")
+	n, _ := strconv.Atoi(minor)
+	return n
+}
+
+// parseGoDirective returns the version specified by the go directive in the provided go.mod content. Returns the empty
+// string if the content does not contain a go directive.
+func parseGoDirective(relPath string, goMod []byte) (string, error) {
+	modFile, err := modfile.Parse(relPath, goMod, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse %s", relPath)
+	}
+	if modFile.Go == nil {
+		return "", nil
+	}
+	return modFile.Go.Version, nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoVersionPolicyExceedsMax(t *testing.T) {
+	for i, tc := range []struct {
+		version string
+		max     string
+		want    bool
+	}{
+		{"1.22", "1.22", false},
+		{"1.22.5", "1.22", false},
+		{"1.23.0", "1.22", true},
+		{"1.22.5", "1.22.4", true},
+		{"1.21", "1.22.4", false},
+		{"1.22rc1", "1.22", false},
+		{"1.22rc1", "1.21.9", true},
+		{"1.23rc1", "1.22", true},
+	} {
+		assert.Equal(t, tc.want, GoVersionPolicyParams{Max: tc.max}.exceedsMax(tc.version), "Case %d: %s <= %s", i, tc.version, tc.max)
+	}
+}
+
+func TestGoVersionViolation(t *testing.T) {
+	buildList := []buildListModule{
+		{Path: "github.com/mod/test", Main: true, GoVersion: "1.23"},
+		{Path: "github.com/pkg/errors", Version: "v0.9.1", GoVersion: "1.13"},
+		{Path: "golang.org/x/mod", Version: "v0.20.0", GoVersion: "1.22.0"},
+		{Path: "golang.org/x/tools", Version: "v0.25.0", GoVersion: "1.23.0"},
+		{Path: "golang.org/x/sync", Version: "v0.8.0", GoVersion: "1.24"},
+	}
+	for i, tc := range []struct {
+		name   string
+		policy GoVersionPolicyParams
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name:   "within range",
+			policy: GoVersionPolicyParams{Min: "1.21", Max: "1.22"},
+			before: "1.21",
+			after:  "1.22.0",
+		},
+		{
+			name:   "older than minimum",
+			policy: GoVersionPolicyParams{Min: "1.21"},
+			before: "1.20",
+			after:  "1.20",
+			want:   "the go directive is 1.20, which is older than the minimum allowed version 1.21",
+		},
+		{
+			name:   "tidy bumps beyond maximum",
+			policy: GoVersionPolicyParams{Max: "1.22"},
+			before: "1.22",
+			after:  "1.24",
+			want: "go mod tidy would change the go directive from 1.22 to 1.24, which is newer than the maximum allowed version 1.22\n" +
+				"    golang.org/x/sync@v0.8.0 requires go 1.24\n" +
+				"    golang.org/x/tools@v0.25.0 requires go 1.23.0",
+		},
+	} {
+		assert.Equal(t, tc.want, goVersionViolation(tc.policy, tc.before, tc.after, buildList), "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestGoVersionAlignmentFailure(t *testing.T) {
+	for i, tc := range []struct {
+		name    string
+		maxSkew int
+		modules []ModuleReport
+		want    string
+	}{
+		{
+			name: "identical go directives",
+			modules: []ModuleReport{
+				{Dir: ".", GoVersion: "1.22"},
+				{Dir: "tools", GoVersion: "1.22"},
+			},
+		},
+		{
+			name: "modules without go directives are ignored",
+			modules: []ModuleReport{
+				{Dir: ".", GoVersion: "1.22"},
+				{Dir: "tools"},
+			},
+		},
+		{
+			name: "different go directives",
+			modules: []ModuleReport{
+				{Dir: ".", GoVersion: "1.22"},
+				{Dir: "tools", GoVersion: "1.22.5"},
+				{Dir: "api", GoVersion: "1.22"},
+			},
+			want: "the go directives of the modules do not agree:\n" +
+				"    tools/go.mod: go 1.22.5\n" +
+				"    go.mod: go 1.22\n" +
+				"    api/go.mod: go 1.22",
+		},
+		{
+			name:    "within maximum skew",
+			maxSkew: 1,
+			modules: []ModuleReport{
+				{Dir: ".", GoVersion: "1.21.3"},
+				{Dir: "tools", GoVersion: "1.22.0"},
+			},
+		},
+		{
+			name:    "exceeds maximum skew",
+			maxSkew: 1,
+			modules: []ModuleReport{
+				{Dir: ".", GoVersion: "1.20"},
+				{Dir: "tools", GoVersion: "1.21"},
+				{Dir: "api", GoVersion: "1.22.1"},
+			},
+			want: "the language versions of the go directives of the modules differ by more than 1:\n" +
+				"    api/go.mod: go 1.22.1\n" +
+				"    tools/go.mod: go 1.21\n" +
+				"    go.mod: go 1.20",
+		},
+	} {
+		got := goVersionAlignmentFailure(GoVersionPolicyParams{Align: true, MaxSkew: tc.maxSkew}, tc.modules)
+		assert.Equal(t, tc.want, got, "Case %d: %s", i, tc.name)
+	}
+}
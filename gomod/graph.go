@@ -0,0 +1,356 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GraphFormat is the format in which a dependency graph is rendered.
+type GraphFormat string
+
+const (
+	// GraphFormatDOT renders the graph in the Graphviz DOT language.
+	GraphFormatDOT GraphFormat = "dot"
+	// GraphFormatMermaid renders the graph as a Mermaid flowchart.
+	GraphFormatMermaid GraphFormat = "mermaid"
+	// GraphFormatJSON renders the graph as JSON.
+	GraphFormatJSON GraphFormat = "json"
+)
+
+// ParseGraphFormat returns the GraphFormat represented by the provided string.
+func ParseGraphFormat(in string) (GraphFormat, error) {
+	switch format := GraphFormat(in); format {
+	case GraphFormatDOT, GraphFormatMermaid, GraphFormatJSON:
+		return format, nil
+	default:
+		return "", errors.Errorf("%q is not a valid graph format: must be one of %q, %q or %q", in, GraphFormatDOT, GraphFormatMermaid, GraphFormatJSON)
+	}
+}
+
+// DependencyFilter specifies which dependencies are included in a dependency graph.
+type DependencyFilter string
+
+const (
+	// DependencyFilterAll includes every dependency.
+	DependencyFilterAll DependencyFilter = "all"
+	// DependencyFilterDirect only includes the dependencies that are required directly by the main module.
+	DependencyFilterDirect DependencyFilter = "direct"
+	// DependencyFilterIndirect only includes the dependencies that are not required directly by the main module.
+	DependencyFilterIndirect DependencyFilter = "indirect"
+)
+
+// ParseDependencyFilter returns the DependencyFilter represented by the provided string. The empty string is parsed as
+// DependencyFilterAll.
+func ParseDependencyFilter(in string) (DependencyFilter, error) {
+	switch filter := DependencyFilter(in); filter {
+	case "":
+		return DependencyFilterAll, nil
+	case DependencyFilterAll, DependencyFilterDirect, DependencyFilterIndirect:
+		return filter, nil
+	default:
+		return "", errors.Errorf("%q is not a valid dependency filter: must be one of %q, %q or %q", in, DependencyFilterAll, DependencyFilterDirect, DependencyFilterIndirect)
+	}
+}
+
+// GraphParams specifies the dependencies that are included in a dependency graph. The main module is always included
+// and an edge is included only if both of the modules it connects are included.
+type GraphParams struct {
+	// MaxDepth is the maximum number of requirements between the main module and an included dependency. If it is less
+	// than 1, the depth is not limited.
+	MaxDepth int
+	// Dependencies specifies whether direct or indirect dependencies are included.
+	Dependencies DependencyFilter
+	// Prefixes are the module path prefixes of the included dependencies. If empty, dependencies with any module path
+	// are included.
+	Prefixes []string
+}
+
+// DependencyGraph is the graph of the selected build list of every module in a project.
+type DependencyGraph struct {
+	// ProjectDir is the absolute path to the project directory.
+	ProjectDir string `json:"projectDir"`
+	// Modules are the graphs of the modules in the project.
+	Modules []ModuleGraph `json:"modules"`
+}
+
+// ModuleGraph is the graph of the selected build list of a single module. Every module in the build list is a single
+// node at its selected version and every edge is a requirement of the selected version of a module.
+type ModuleGraph struct {
+	// Path is the module path.
+	Path string `json:"path"`
+	// Dir is the directory of the module relative to the project directory.
+	Dir string `json:"dir"`
+	// Nodes are the modules in the graph in the order of the build list, starting with the main module.
+	Nodes []GraphNode `json:"nodes"`
+	// Edges are the requirements between the modules in the graph.
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a module in a ModuleGraph.
+type GraphNode struct {
+	// Path is the module path.
+	Path string `json:"path"`
+	// Version is the selected version. It is empty for the main module.
+	Version string `json:"version,omitempty"`
+	// Main is true for the main module.
+	Main bool `json:"main,omitempty"`
+	// Direct is true if the module is required directly by the main module.
+	Direct bool `json:"direct,omitempty"`
+	// Depth is the smallest number of requirements between the main module and the module.
+	Depth int `json:"depth"`
+}
+
+// GraphEdge is a requirement of one module in a ModuleGraph on another.
+type GraphEdge struct {
+	// From is the module path of the module that has the requirement.
+	From string `json:"from"`
+	// To is the module path of the required module.
+	To string `json:"to"`
+}
+
+// Graph returns the graph of the selected build list of every module in projectDir based on "go mod graph" and
+// "go list -m -json all", filtered according to graphParams.
+func Graph(ctx context.Context, projectDir string, graphParams GraphParams, params Params) (*DependencyGraph, error) {
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine absolute path of project directory")
+	}
+	modules, err := discoverModules(projectDir, params.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	if env := toolchainEnv(false, params.Toolchain); len(env) > 0 {
+		params.Executor = envExecutor{Executor: params.executor(), env: env}
+	}
+
+	graph := &DependencyGraph{
+		ProjectDir: projectDir,
+		Modules:    make([]ModuleGraph, len(modules)),
+	}
+	moduleErrs := make([]error, len(modules))
+	forEachParallel(len(modules), params.Concurrency, func(i int) {
+		graph.Modules[i], moduleErrs[i] = moduleGraph(ctx, params.executor(), modules[i], graphParams)
+	})
+	var errs []error
+	for i, mod := range modules {
+		if moduleErrs[i] != nil {
+			errs = append(errs, &ModuleError{Module: mod.Path, Err: moduleErrs[i]})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, joinErrors(errs)
+	}
+	return graph, nil
+}
+
+// moduleGraph returns the filtered graph of the selected build list of the provided module. The "-mod=readonly" flag
+// is used so that the build list is determined from go.mod even if the module is vendored.
+func moduleGraph(ctx context.Context, executor Executor, mod module, graphParams GraphParams) (ModuleGraph, error) {
+	buildList, err := listModules(ctx, executor, mod.Dir, "-mod=readonly", "all")
+	if err != nil {
+		return ModuleGraph{}, err
+	}
+	reqGraph, err := readRequirementGraph(ctx, executor, mod.Dir)
+	if err != nil {
+		return ModuleGraph{}, err
+	}
+	graph := selectedGraph(buildList, reqGraph)
+	graph.Path, graph.Dir = mod.Path, mod.RelDir
+	return graph.filter(graphParams), nil
+}
+
+// selectedGraph returns the graph of the provided build list, which contains the requirements of the selected version
+// of every module in the requirement graph. The depth of every module is computed from the requirements.
+func selectedGraph(buildList []buildListModule, reqGraph *requirementGraph) ModuleGraph {
+	var graph ModuleGraph
+	selected := make(map[string]string)
+	for _, mod := range buildList {
+		selected[mod.Path] = mod.Version
+	}
+	edges := make(map[string][]string)
+	for _, mod := range buildList {
+		from := moduleVersionString(mod.Path, mod.Version)
+		if mod.Main {
+			from = reqGraph.root
+		}
+		seen := make(map[string]bool)
+		for _, to := range reqGraph.edges[from] {
+			toPath := nodePath(to)
+			if _, ok := selected[toPath]; !ok || seen[toPath] || toPath == mod.Path {
+				continue
+			}
+			seen[toPath] = true
+			edges[mod.Path] = append(edges[mod.Path], toPath)
+			graph.Edges = append(graph.Edges, GraphEdge{From: mod.Path, To: toPath})
+		}
+	}
+
+	// the depth of every module is the length of the shortest path from the main module
+	depths := make(map[string]int)
+	var queue []string
+	for _, mod := range buildList {
+		if mod.Main {
+			depths[mod.Path] = 0
+			queue = append(queue, mod.Path)
+		}
+	}
+	for len(queue) > 0 {
+		modPath := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[modPath] {
+			if _, ok := depths[next]; !ok {
+				depths[next] = depths[modPath] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	for _, mod := range buildList {
+		depth, ok := depths[mod.Path]
+		if !ok {
+			// a module that is not reachable through requirements (which should not happen) is placed below the main
+			// module
+			depth = 1
+		}
+		node := GraphNode{
+			Path:   mod.Path,
+			Main:   mod.Main,
+			Direct: !mod.Main && !mod.Indirect,
+			Depth:  depth,
+		}
+		if !mod.Main {
+			node.Version = mod.Version
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	return graph
+}
+
+// filter returns the graph with only the nodes included by the provided parameters and the edges between them.
+func (g ModuleGraph) filter(params GraphParams) ModuleGraph {
+	filtered := ModuleGraph{
+		Path: g.Path,
+		Dir:  g.Dir,
+	}
+	included := make(map[string]bool)
+	for _, node := range g.Nodes {
+		if node.Main || params.includes(node) {
+			included[node.Path] = true
+			filtered.Nodes = append(filtered.Nodes, node)
+		}
+	}
+	for _, edge := range g.Edges {
+		if included[edge.From] && included[edge.To] {
+			filtered.Edges = append(filtered.Edges, edge)
+		}
+	}
+	return filtered
+}
+
+// includes returns true if the provided dependency is included by the parameters.
+func (p GraphParams) includes(node GraphNode) bool {
+	if p.MaxDepth > 0 && node.Depth > p.MaxDepth {
+		return false
+	}
+	switch p.Dependencies {
+	case DependencyFilterDirect:
+		if !node.Direct {
+			return false
+		}
+	case DependencyFilterIndirect:
+		if node.Direct {
+			return false
+		}
+	}
+	if len(p.Prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range p.Prefixes {
+		if strings.HasPrefix(node.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// label returns the label of the node, which is its module path followed by its version (if any).
+func (n GraphNode) label() string {
+	return moduleVersionString(n.Path, n.Version)
+}
+
+// Write writes the graph to w in the provided format.
+func (g *DependencyGraph) Write(w io.Writer, format GraphFormat) error {
+	var out string
+	switch format {
+	case GraphFormatJSON:
+		jsonBytes, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal graph as JSON")
+		}
+		out = string(jsonBytes) + "\n"
+	case GraphFormatMermaid:
+		out = g.mermaid()
+	default:
+		out = g.dot()
+	}
+	if _, err := io.WriteString(w, out); err != nil {
+		return errors.Wrapf(err, "failed to write graph")
+	}
+	return nil
+}
+
+// dot returns the graph in the Graphviz DOT language with a digraph for every module. The dependencies that are not
+// required directly by the main module are drawn with dashed outlines.
+func (g *DependencyGraph) dot() string {
+	buf := &strings.Builder{}
+	for i, mod := range g.Modules {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		_, _ = fmt.Fprintf(buf, "digraph %q {\n", mod.Path)
+		buf.WriteString("  rankdir=LR;\n")
+		buf.WriteString("  node [shape=box];\n")
+		for _, node := range mod.Nodes {
+			var attrs string
+			if !node.Main && !node.Direct {
+				attrs = ", style=dashed"
+			}
+			_, _ = fmt.Fprintf(buf, "  %q [label=%q%s];\n", node.Path, node.label(), attrs)
+		}
+		for _, edge := range mod.Edges {
+			_, _ = fmt.Fprintf(buf, "  %q -> %q;\n", edge.From, edge.To)
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.String()
+}
+
+// mermaid returns the graph as a single Mermaid flowchart with a subgraph for every module. The nodes are identified
+// by the index of their module and their index within it because module paths are not valid Mermaid node identifiers
+// and a module may be part of the graphs of several modules.
+func (g *DependencyGraph) mermaid() string {
+	buf := &strings.Builder{}
+	buf.WriteString("graph LR\n")
+	for i, mod := range g.Modules {
+		_, _ = fmt.Fprintf(buf, "  subgraph m%d[\"%s\"]\n", i, mod.Path)
+		ids := make(map[string]string)
+		for j, node := range mod.Nodes {
+			ids[node.Path] = fmt.Sprintf("m%dn%d", i, j)
+			_, _ = fmt.Fprintf(buf, "    %s[\"%s\"]\n", ids[node.Path], node.label())
+		}
+		for _, edge := range mod.Edges {
+			_, _ = fmt.Fprintf(buf, "    %s --> %s\n", ids[edge.From], ids[edge.To])
+		}
+		buf.WriteString("  end\n")
+	}
+	return buf.String()
+}
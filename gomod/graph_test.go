@@ -0,0 +1,163 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testGraphBuildList = []buildListModule{
+	{Path: "github.com/mod/test", Main: true},
+	{Path: "github.com/foo/bar", Version: "v1.0.0"},
+	{Path: "github.com/foo/baz", Version: "v1.1.0", Indirect: true},
+	{Path: "github.com/foo/qux", Version: "v0.1.0", Indirect: true},
+	{Path: "github.com/pkg/errors", Version: "v0.9.1"},
+}
+
+func TestSelectedGraph(t *testing.T) {
+	graph := selectedGraph(testGraphBuildList, parseModGraph([]byte(testModGraph)))
+	assert.Equal(t, []GraphNode{
+		{Path: "github.com/mod/test", Main: true},
+		{Path: "github.com/foo/bar", Version: "v1.0.0", Direct: true, Depth: 1},
+		{Path: "github.com/foo/baz", Version: "v1.1.0", Depth: 2},
+		{Path: "github.com/foo/qux", Version: "v0.1.0", Depth: 3},
+		{Path: "github.com/pkg/errors", Version: "v0.9.1", Direct: true, Depth: 1},
+	}, graph.Nodes)
+	// the requirement of github.com/foo/bar on github.com/pkg/errors@v0.8.0 is an edge to the selected version
+	assert.Equal(t, []GraphEdge{
+		{From: "github.com/mod/test", To: "github.com/foo/bar"},
+		{From: "github.com/mod/test", To: "github.com/pkg/errors"},
+		{From: "github.com/foo/bar", To: "github.com/foo/baz"},
+		{From: "github.com/foo/bar", To: "github.com/pkg/errors"},
+		{From: "github.com/foo/baz", To: "github.com/foo/qux"},
+	}, graph.Edges)
+}
+
+func TestModuleGraphFilter(t *testing.T) {
+	graph := selectedGraph(testGraphBuildList, parseModGraph([]byte(testModGraph)))
+	for i, tc := range []struct {
+		name      string
+		params    GraphParams
+		wantNodes []string
+		wantEdges []GraphEdge
+	}{
+		{
+			name:      "depth",
+			params:    GraphParams{MaxDepth: 1},
+			wantNodes: []string{"github.com/mod/test", "github.com/foo/bar", "github.com/pkg/errors"},
+			wantEdges: []GraphEdge{
+				{From: "github.com/mod/test", To: "github.com/foo/bar"},
+				{From: "github.com/mod/test", To: "github.com/pkg/errors"},
+				{From: "github.com/foo/bar", To: "github.com/pkg/errors"},
+			},
+		},
+		{
+			name:      "indirect",
+			params:    GraphParams{Dependencies: DependencyFilterIndirect},
+			wantNodes: []string{"github.com/mod/test", "github.com/foo/baz", "github.com/foo/qux"},
+			wantEdges: []GraphEdge{
+				{From: "github.com/foo/baz", To: "github.com/foo/qux"},
+			},
+		},
+		{
+			name:      "prefix",
+			params:    GraphParams{Dependencies: DependencyFilterDirect, Prefixes: []string{"github.com/foo/"}},
+			wantNodes: []string{"github.com/mod/test", "github.com/foo/bar"},
+			wantEdges: []GraphEdge{
+				{From: "github.com/mod/test", To: "github.com/foo/bar"},
+			},
+		},
+	} {
+		filtered := graph.filter(tc.params)
+		var nodes []string
+		for _, node := range filtered.Nodes {
+			nodes = append(nodes, node.Path)
+		}
+		assert.Equal(t, tc.wantNodes, nodes, "Case %d: %s", i, tc.name)
+		assert.Equal(t, tc.wantEdges, filtered.Edges, "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestDependencyGraphWrite(t *testing.T) {
+	graph := &DependencyGraph{
+		Modules: []ModuleGraph{
+			{
+				Path: "github.com/mod/test",
+				Dir:  ".",
+				Nodes: []GraphNode{
+					{Path: "github.com/mod/test", Main: true},
+					{Path: "github.com/foo/bar", Version: "v1.0.0", Direct: true, Depth: 1},
+					{Path: "github.com/foo/baz", Version: "v1.1.0", Depth: 2},
+				},
+				Edges: []GraphEdge{
+					{From: "github.com/mod/test", To: "github.com/foo/bar"},
+					{From: "github.com/foo/bar", To: "github.com/foo/baz"},
+				},
+			},
+			{
+				Path: "github.com/mod/test/tools",
+				Dir:  "tools",
+				Nodes: []GraphNode{
+					{Path: "github.com/mod/test/tools", Main: true},
+					{Path: "github.com/foo/bar", Version: "v1.0.0", Direct: true, Depth: 1},
+				},
+				Edges: []GraphEdge{
+					{From: "github.com/mod/test/tools", To: "github.com/foo/bar"},
+				},
+			},
+		},
+	}
+	for i, tc := range []struct {
+		format GraphFormat
+		want   string
+	}{
+		{
+			format: GraphFormatDOT,
+			want: `digraph "github.com/mod/test" {
+  rankdir=LR;
+  node [shape=box];
+  "github.com/mod/test" [label="github.com/mod/test"];
+  "github.com/foo/bar" [label="github.com/foo/bar@v1.0.0"];
+  "github.com/foo/baz" [label="github.com/foo/baz@v1.1.0", style=dashed];
+  "github.com/mod/test" -> "github.com/foo/bar";
+  "github.com/foo/bar" -> "github.com/foo/baz";
+}
+
+digraph "github.com/mod/test/tools" {
+  rankdir=LR;
+  node [shape=box];
+  "github.com/mod/test/tools" [label="github.com/mod/test/tools"];
+  "github.com/foo/bar" [label="github.com/foo/bar@v1.0.0"];
+  "github.com/mod/test/tools" -> "github.com/foo/bar";
+}
+`,
+		},
+		{
+			format: GraphFormatMermaid,
+			want: `graph LR
+  subgraph m0["github.com/mod/test"]
+    m0n0["github.com/mod/test"]
+    m0n1["github.com/foo/bar@v1.0.0"]
+    m0n2["github.com/foo/baz@v1.1.0"]
+    m0n0 --> m0n1
+    m0n1 --> m0n2
+  end
+  subgraph m1["github.com/mod/test/tools"]
+    m1n0["github.com/mod/test/tools"]
+    m1n1["github.com/foo/bar@v1.0.0"]
+    m1n0 --> m1n1
+  end
+`,
+		},
+	} {
+		buf := &bytes.Buffer{}
+		require.NoError(t, graph.Write(buf, tc.format), "Case %d: %s", i, tc.format)
+		assert.Equal(t, tc.want, buf.String(), "Case %d: %s", i, tc.format)
+	}
+}
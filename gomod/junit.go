@@ -0,0 +1,131 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// junitProjectSuiteName is the name of the JUnit test suite that contains the checks that apply to the whole project.
+const junitProjectSuiteName = "project"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",cdata"`
+}
+
+// WriteJUnit writes the verification results of the report to w as a JUnit XML report. Every module (and the go.work
+// file, if any) is a test suite with a test case for each of its checks. The checks that apply to the whole project
+// are reported in a test suite named "project". A module that failed with an error is reported as a test case with an
+// error.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suites := junitTestSuites{
+		Name: "mod",
+		Time: junitTime(r.DurationSeconds),
+	}
+	if len(r.Checks) > 0 {
+		suites.Suites = append(suites.Suites, newJUnitTestSuite(junitProjectSuiteName, r.Checks, "", 0))
+	}
+	for _, modReport := range r.Modules {
+		suites.Suites = append(suites.Suites, newJUnitTestSuite(modReport.Path, modReport.Checks, modReport.Error, modReport.DurationSeconds))
+	}
+	if r.Workspace != nil {
+		suites.Suites = append(suites.Suites, newJUnitTestSuite(r.Workspace.Path, r.Workspace.Checks, r.Workspace.Error, r.Workspace.DurationSeconds))
+	}
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+	}
+
+	out, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal report as JUnit XML")
+	}
+	if _, err := fmt.Fprintf(w, "%s%s\n", xml.Header, out); err != nil {
+		return errors.Wrapf(err, "failed to write report")
+	}
+	return nil
+}
+
+func newJUnitTestSuite(name string, checks []CheckResult, errMsg string, durationSeconds float64) junitTestSuite {
+	suite := junitTestSuite{
+		Name: name,
+		Time: junitTime(durationSeconds),
+	}
+	for _, check := range checks {
+		testCase := junitTestCase{
+			ClassName: name,
+			Name:      check.Name,
+			Time:      junitTime(0),
+		}
+		switch check.Status {
+		case CheckFailed:
+			testCase.Failure = &junitMessage{Message: firstLine(check.Message), Content: check.Message}
+			suite.Failures++
+		case CheckSkipped:
+			testCase.Skipped = &junitMessage{Message: check.Message}
+			suite.Skipped++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	if errMsg != "" {
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			ClassName: name,
+			Name:      "mod",
+			Time:      junitTime(durationSeconds),
+			Error:     &junitMessage{Message: firstLine(errMsg), Content: errMsg},
+		})
+		suite.Errors++
+	}
+	suite.Tests = len(suite.TestCases)
+	return suite
+}
+
+func junitTime(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+// firstLine returns the first line of s without a trailing colon, which is used as the summary of a message.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSuffix(line, ":")
+}
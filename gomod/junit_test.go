@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	report := &Report{
+		DurationSeconds: 1.5,
+		Checks: []CheckResult{
+			{Name: "toolchain", Status: CheckPassed},
+		},
+		Modules: []ModuleReport{
+			{
+				Path:            "github.com/mod/test",
+				DurationSeconds: 1,
+				Checks: []CheckResult{
+					{Name: "go.mod", Status: CheckFailed, Message: "go.mod modified:\n--- a/go.mod\n+++ b/go.mod"},
+					{Name: "go.sum", Status: CheckPassed},
+					{Name: "vendor", Status: CheckSkipped, Message: "go.mod or go.sum is not up-to-date"},
+				},
+			},
+			{
+				Path:            "github.com/mod/test/tools",
+				DurationSeconds: 0.25,
+				Error:           "\"go mod tidy\" failed with exit code 1 in /project/tools",
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.WriteJUnit(buf))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="mod" tests="5" failures="1" errors="1" skipped="1" time="1.500">
+  <testsuite name="project" tests="1" failures="0" errors="0" skipped="0" time="0.000">
+    <testcase classname="project" name="toolchain" time="0.000"></testcase>
+  </testsuite>
+  <testsuite name="github.com/mod/test" tests="3" failures="1" errors="0" skipped="1" time="1.000">
+    <testcase classname="github.com/mod/test" name="go.mod" time="0.000">
+      <failure message="go.mod modified"><![CDATA[go.mod modified:
+--- a/go.mod
++++ b/go.mod]]></failure>
+    </testcase>
+    <testcase classname="github.com/mod/test" name="go.sum" time="0.000"></testcase>
+    <testcase classname="github.com/mod/test" name="vendor" time="0.000">
+      <skipped message="go.mod or go.sum is not up-to-date"></skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="github.com/mod/test/tools" tests="1" failures="0" errors="1" skipped="0" time="0.250">
+    <testcase classname="github.com/mod/test/tools" name="mod" time="0.250">
+      <error message="&#34;go mod tidy&#34; failed with exit code 1 in /project/tools"><![CDATA["go mod tidy" failed with exit code 1 in /project/tools]]></error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// majorUpgrade is the upgrade of a module to a newer major version, which has a different module path.
+type majorUpgrade struct {
+	// Path is the module path of the current version (such as "example.com/mod").
+	Path string
+	// NewPath is the module path of the newer major version (such as "example.com/mod/v3").
+	NewPath string
+	// Version is the latest version of NewPath (such as "v3.1.0").
+	Version string
+}
+
+// majorUpgrades returns the upgrades of the modules in the provided build list that match modPatterns (and do not match
+// exclude) to their latest newer major version (see latestMajorVersions). The main module and replaced modules are
+// never upgraded.
+func majorUpgrades(ctx context.Context, executor Executor, dir string, buildList []buildListModule, modPatterns, exclude []string) ([]majorUpgrade, error) {
+	candidates := upgradeCandidates(buildList, modPatterns, exclude)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	latest, err := latestMajorVersions(ctx, executor, dir, candidates)
+	if err != nil {
+		return nil, err
+	}
+	var upgrades []majorUpgrade
+	for _, mod := range candidates {
+		newPath, newVersion, ok := strings.Cut(latest[mod.Path], "@")
+		if !ok {
+			continue
+		}
+		upgrades = append(upgrades, majorUpgrade{
+			Path:    mod.Path,
+			NewPath: newPath,
+			Version: newVersion,
+		})
+	}
+	return upgrades, nil
+}
+
+// rewriteImports returns the changes to the Go source files of the module in dir that replace the import paths of the
+// packages of the upgraded modules with the corresponding import paths of their newer major versions: for example,
+// "example.com/mod/sub" is replaced with "example.com/mod/v3/sub". The module that provides an imported package is the
+// module in the provided build list with the longest path that is a prefix of the import path. The directories that are
+// ignored by the go command and the directories of nested modules are skipped. Only the import paths are modified, so
+// the rest of the files (including their formatting) is preserved.
+func rewriteImports(dir string, buildList []buildListModule, upgrades []majorUpgrade) ([]fileChange, error) {
+	if len(upgrades) == 0 {
+		return nil, nil
+	}
+	var changes []fileChange
+	if err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", path)
+		}
+		if d.IsDir() {
+			if path == dir {
+				return nil
+			}
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !strings.HasSuffix(d.Name(), ".go") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", path)
+		}
+		rewritten, err := rewriteFileImports(path, content, buildList, upgrades)
+		if err != nil {
+			return err
+		}
+		if rewritten != nil {
+			changes = append(changes, fileChange{
+				path:   path,
+				before: content,
+				after:  rewritten,
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to rewrite imports in %s", dir)
+	}
+	return changes, nil
+}
+
+// rewriteFileImports returns the provided content of the Go source file at path with the import paths of the packages
+// of the upgraded modules replaced (see rewriteImports). Returns nil if no import paths are replaced.
+func rewriteFileImports(path string, content []byte, buildList []buildListModule, upgrades []majorUpgrade) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.ImportsOnly)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+	type replacement struct {
+		start, end int
+		value      string
+	}
+	var replacements []replacement
+	for _, imp := range file.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse import path %s in %s", imp.Path.Value, path)
+		}
+		modPath := importModulePath(importPath, buildList)
+		for _, upgrade := range upgrades {
+			if modPath != upgrade.Path {
+				continue
+			}
+			replacements = append(replacements, replacement{
+				start: fset.Position(imp.Path.Pos()).Offset,
+				end:   fset.Position(imp.Path.End()).Offset,
+				value: strconv.Quote(upgrade.NewPath + strings.TrimPrefix(importPath, upgrade.Path)),
+			})
+		}
+	}
+	if len(replacements) == 0 {
+		return nil, nil
+	}
+	// replace from the end of the file so that the offsets of the remaining replacements stay valid
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start > replacements[j].start
+	})
+	rewritten := append([]byte(nil), content...)
+	for _, r := range replacements {
+		rewritten = append(rewritten[:r.start], append([]byte(r.value), rewritten[r.end:]...)...)
+	}
+	return rewritten, nil
+}
+
+// importModulePath returns the path of the module in the provided build list that provides the package with the
+// provided import path, which is the longest module path that is the import path or a prefix of it. Returns the empty
+// string if no module in the build list provides the package.
+func importModulePath(importPath string, buildList []buildListModule) string {
+	var modPath string
+	for _, mod := range buildList {
+		if (importPath == mod.Path || strings.HasPrefix(importPath, mod.Path+"/")) && len(mod.Path) > len(modPath) {
+			modPath = mod.Path
+		}
+	}
+	return modPath
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteImports(t *testing.T) {
+	projectDir := t.TempDir()
+	writeTestFiles(projectDir, map[string]string{
+		"go.mod": "module github.com/mod/test\n",
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/pkg/errors/sub"
+	"github.com/pkg/errors/other/pkg"
+	"github.com/pkg/errorsx"
+)
+`,
+		"internal/lib.go":       "package internal\n\nimport \"gopkg.in/yaml.v2\"\n",
+		"internal/unchanged.go": "package internal\n\nimport \"fmt\"\n",
+		"internal/README.md":    "import \"github.com/pkg/errors\"\n",
+		"vendor/foo/foo.go":     "package foo\n\nimport \"github.com/pkg/errors\"\n",
+		"testdata/foo.go":       "package foo\n\nimport \"github.com/pkg/errors\"\n",
+		"nested/go.mod":         "module github.com/mod/test/nested\n",
+		"nested/nested.go":      "package nested\n\nimport \"github.com/pkg/errors\"\n",
+	})
+	buildList := []buildListModule{
+		{Path: "github.com/mod/test", Main: true},
+		{Path: "github.com/pkg/errors", Version: "v0.9.1"},
+		{Path: "github.com/pkg/errors/other", Version: "v1.0.0"},
+		{Path: "github.com/pkg/errorsx", Version: "v1.0.0"},
+		{Path: "gopkg.in/yaml.v2", Version: "v2.4.0"},
+	}
+	upgrades := []majorUpgrade{
+		{Path: "github.com/pkg/errors", NewPath: "github.com/pkg/errors/v3", Version: "v3.1.0"},
+		{Path: "gopkg.in/yaml.v2", NewPath: "gopkg.in/yaml.v3", Version: "v3.0.1"},
+	}
+
+	changes, err := rewriteImports(projectDir, buildList, upgrades)
+	require.NoError(t, err)
+	got := make(map[string]string)
+	for _, change := range changes {
+		relPath, err := filepath.Rel(projectDir, change.path)
+		require.NoError(t, err)
+		got[filepath.ToSlash(relPath)] = string(change.after)
+	}
+	assert.Equal(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	pkgerrors "github.com/pkg/errors/v3"
+	"github.com/pkg/errors/v3/sub"
+	"github.com/pkg/errors/other/pkg"
+	"github.com/pkg/errorsx"
+)
+`,
+		"internal/lib.go": "package internal\n\nimport \"gopkg.in/yaml.v3\"\n",
+	}, got)
+}
+
+func TestImportModulePath(t *testing.T) {
+	buildList := []buildListModule{
+		{Path: "github.com/mod/test", Main: true},
+		{Path: "github.com/pkg/errors"},
+		{Path: "github.com/pkg/errors/other"},
+	}
+	for i, tc := range []struct {
+		importPath string
+		want       string
+	}{
+		{"github.com/pkg/errors", "github.com/pkg/errors"},
+		{"github.com/pkg/errors/sub", "github.com/pkg/errors"},
+		{"github.com/pkg/errors/other/sub", "github.com/pkg/errors/other"},
+		{"github.com/pkg/errorsx", ""},
+		{"github.com/mod/test/internal", "github.com/mod/test"},
+		{"fmt", ""},
+	} {
+		assert.Equal(t, tc.want, importModulePath(tc.importPath, buildList), "Case %d: %s", i, tc.importPath)
+	}
+}
@@ -5,137 +5,658 @@
 package gomod
 
 import (
-	"crypto/sha256"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
-	"path"
-	"reflect"
-	"slices"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/palantir/godel/v2/pkg/dirchecksum"
 	"github.com/pkg/errors"
 )
 
-func Run(projectDir string, verify bool, stdout io.Writer) error {
-	var goModChecksumBefore, goSumChecksumBefore [32]byte
+// Run runs "go mod tidy" followed by "go mod vendor" (if vendor mode is enabled by the provided parameters) for every
+// module in projectDir. If verify is true, the operations are performed against a scratch copy of the module files and
+// an error is returned if the result differs from the current state of the project for any of the modules.
+// Verification never modifies the go.mod, go.sum or vendor paths of the project.
+//
+// The go.mod, go.sum and vendor paths of the project are only modified once the go commands for every module (and for
+// the workspace, if any) have succeeded. The commands are run using the provided context: if the context is cancelled
+// (for example, because the process was interrupted or a timeout was reached) or a command fails, the running commands
+// are killed and the project is left in the state it was in before Run was called. If writing the changes fails, the
+// changes that were already written are rolled back.
+func Run(ctx context.Context, projectDir string, verify bool, params Params, stdout io.Writer) error {
+	_, err := RunWithReport(ctx, projectDir, verify, params, stdout)
+	return err
+}
+
+// RunWithReport performs the same operations as Run and returns a Report that records the steps that were run, the
+// vendor decision for every module and the files that were changed (or, in verify mode, that would be changed). The
+// returned report is never nil and its Error field records the returned error, if any.
+func RunWithReport(ctx context.Context, projectDir string, verify bool, params Params, stdout io.Writer) (*Report, error) {
+	start := time.Now()
+	report := &Report{
+		ProjectDir: projectDir,
+		Verify:     verify,
+	}
+	err := runProject(ctx, projectDir, verify, params, stdout, report)
+	report.DurationSeconds = time.Since(start).Seconds()
+	report.Error = errorString(err)
+	return report, err
+}
+
+func runProject(ctx context.Context, projectDir string, verify bool, params Params, stdout io.Writer, report *Report) (rErr error) {
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to determine absolute path of project directory")
+	}
+	report.ProjectDir = projectDir
+	modules, err := discoverModules(projectDir, params.Exclude)
+	if err != nil {
+		return err
+	}
+	ws, err := readWorkspace(projectDir)
+	if err != nil {
+		return err
+	}
+	if env := toolchainEnv(verify, params.Toolchain); len(env) > 0 {
+		params.Executor = envExecutor{Executor: params.executor(), env: env}
+	}
+
+	// the toolchain directives are checked before running any commands, which may fail if the toolchain is too old
 	if verify {
-		var err error
-		goModChecksumBefore, goSumChecksumBefore, err = goModChecksums(projectDir)
-		if err != nil {
+		if report.Failures, err = verifyToolchain(ctx, params, modules, ws); err != nil {
 			return err
 		}
+		report.Checks = append(report.Checks, newCheckResult(checkToolchain, strings.Join(report.Failures, "\n")))
+	}
+
+	// in apply mode, the updates of the modules and of the workspace (the last element) are only written once all of
+	// them have been generated successfully
+	updates := make([]pendingUpdate, len(modules)+1)
+	defer func() {
+		for i := range updates {
+			if err := updates[i].cleanup(); err != nil && rErr == nil {
+				rErr = err
+			}
+		}
+	}()
+
+	report.Modules = make([]ModuleReport, len(modules))
+	moduleErrs := make([]error, len(modules))
+	forEachParallel(len(modules), params.Concurrency, func(i int) {
+		modReport := &report.Modules[i]
+		modReport.Path, modReport.Dir = modules[i].Path, modules[i].RelDir
+		moduleErrs[i] = runRecorded(params, modReport, func(params Params, stdout io.Writer) error {
+			return runModule(ctx, modules[i], ws, verify, params, stdout, modReport, &updates[i])
+		})
+	})
+
+	// print the output of the modules in a stable order and combine the errors of all of the modules
+	verifyFailures := report.Failures
+	var errs []error
+	for i, mod := range modules {
+		writePrefixed(stdout, mod.Path, []byte(report.Modules[i].Output))
+		verifyFailures = append(verifyFailures, report.Modules[i].Failures...)
+		if moduleErrs[i] != nil {
+			errs = append(errs, &ModuleError{Module: mod.Path, Err: moduleErrs[i]})
+		}
 	}
-	if err := run(stdout, "tidy"); err != nil {
+	if len(errs) > 0 {
+		// report the verification failures that were found before the errors occurred as well
+		if len(verifyFailures) > 0 {
+			errs = append([]error{errors.New(strings.Join(verifyFailures, "\n"))}, errs...)
+		}
+		return joinErrors(errs)
+	}
+	if verify && params.Policy.GoVersion.Align {
+		failure := goVersionAlignmentFailure(params.Policy.GoVersion, report.Modules)
+		report.Checks = append(report.Checks, newCheckResult(checkGoVersionAlignment, failure))
+		if failure != "" {
+			report.Failures = append(report.Failures, failure)
+			verifyFailures = append(verifyFailures, failure)
+		}
+	}
+
+	if ws != nil {
+		report.Workspace = &ModuleReport{Path: "go.work", Dir: "."}
+		err := runRecorded(params, report.Workspace, func(params Params, stdout io.Writer) error {
+			var moduleFiles []fileChange
+			for _, update := range updates[:len(modules)] {
+				moduleFiles = append(moduleFiles, update.files...)
+			}
+			return runWorkspace(ctx, ws, verify, params, stdout, report.Workspace, moduleFiles, &updates[len(modules)])
+		})
+		writePrefixed(stdout, "go.work", []byte(report.Workspace.Output))
+		if err != nil {
+			return errors.Wrapf(err, "go.work")
+		}
+		verifyFailures = append(verifyFailures, report.Workspace.Failures...)
+	}
+	if len(verifyFailures) > 0 {
+		return errors.New(strings.Join(verifyFailures, "\n"))
+	}
+
+	// do not modify the project if the run was interrupted
+	if err := ctx.Err(); err != nil {
 		return err
 	}
-	if verify {
-		goModChecksumAfter, goSumChecksumAfter, err := goModChecksums(projectDir)
+	return writeUpdates(updates)
+}
+
+// writeUpdates writes the provided updates to the project. The files and vendor directories that are modified by the
+// updates are backed up first and restored if writing any of the updates fails, so the project is either fully updated
+// or left in the state it was in before writeUpdates was called.
+func writeUpdates(updates []pendingUpdate) (rErr error) {
+	var paths []string
+	var vendorDirs []string
+	for _, update := range updates {
+		for _, file := range update.files {
+			paths = append(paths, file.path)
+		}
+		if update.newVendorDirPath != "" {
+			vendorDirs = append(vendorDirs, update.vendorDirPath)
+		}
+	}
+	snapshot, err := newFileSnapshot(paths...)
+	if err != nil {
+		return err
+	}
+	var backups []*vendorBackup
+	defer func() {
+		for _, backup := range backups {
+			if err := backup.cleanup(); err != nil && rErr == nil {
+				rErr = err
+			}
+		}
+	}()
+	for _, vendorDir := range vendorDirs {
+		backup, err := newVendorBackup(vendorDir)
 		if err != nil {
 			return err
 		}
-		if !reflect.DeepEqual(goModChecksumBefore, goModChecksumAfter) {
-			return errors.Errorf("go.mod modified")
+		backups = append(backups, backup)
+	}
+	defer func() {
+		if rErr == nil {
+			return
+		}
+		rollbackErrs := []error{errors.Wrapf(rErr, "changes were rolled back")}
+		if err := snapshot.restore(); err != nil {
+			rollbackErrs = append(rollbackErrs, err)
 		}
-		if !reflect.DeepEqual(goSumChecksumBefore, goSumChecksumAfter) {
-			return errors.Errorf("go.sum modified")
+		for _, backup := range backups {
+			if err := backup.restore(); err != nil {
+				rollbackErrs = append(rollbackErrs, err)
+			}
 		}
-	}
+		rErr = joinErrors(rollbackErrs)
+	}()
 
-	// if vendor mode is not set, do not perform vendor operations
-	if !modVendorGoFlagsSet() {
-		return nil
+	for i := range updates {
+		if err := updates[i].write(); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	vendorDirPath := path.Join(projectDir, "vendor")
-	vendorDirExistsBefore := true
-	var vendorChecksumBefore dirchecksum.ChecksumSet
-	if verify {
-		if _, err := os.Stat(vendorDirPath); os.IsNotExist(err) {
-			vendorDirExistsBefore = false
-		} else {
-			var err error
-			vendorChecksumBefore, err = dirchecksum.ChecksumsForMatchingPaths(vendorDirPath, nil)
-			if err != nil {
-				return errors.Wrapf(err, "failed to compute checksums for %s", vendorDirPath)
+// pendingUpdate is the new state of the files of a module or workspace that is written to the project once the go
+// commands for every module and for the workspace have succeeded.
+type pendingUpdate struct {
+	// files are the files to write, where a nil after removes the file.
+	files []fileChange
+	// newVendorDirPath is the path of the generated vendor directory that replaces vendorDirPath. It is empty if the
+	// vendor directory is not updated.
+	newVendorDirPath string
+	vendorDirPath    string
+}
+
+// write writes the files of the update and moves the generated vendor directory into place.
+func (u *pendingUpdate) write() error {
+	for _, file := range u.files {
+		if file.after == nil {
+			if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "failed to remove %s", file.path)
 			}
+			continue
+		}
+		if err := os.WriteFile(file.path, file.after, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write %s", file.path)
 		}
 	}
-	if err := run(stdout, "vendor"); err != nil {
+	if u.newVendorDirPath == "" {
+		return nil
+	}
+	return replaceDir(u.newVendorDirPath, u.vendorDirPath)
+}
+
+// cleanup removes the generated vendor directory if it was not moved into place.
+func (u *pendingUpdate) cleanup() error {
+	if u.newVendorDirPath == "" {
+		return nil
+	}
+	if err := os.RemoveAll(u.newVendorDirPath); err != nil {
+		return errors.Wrapf(err, "failed to remove temporary directory %s", u.newVendorDirPath)
+	}
+	return nil
+}
+
+// runRecorded calls fn with parameters whose Executor records the go commands that are run and with a writer that
+// captures the output. The commands, output, duration and error are recorded in report.
+func runRecorded(params Params, report *ModuleReport, fn func(params Params, stdout io.Writer) error) error {
+	start := time.Now()
+	recorder := &stepRecorder{Executor: params.executor()}
+	params.Executor = recorder
+	output := &bytes.Buffer{}
+	err := fn(params, output)
+	report.Steps = recorder.recordedSteps()
+	report.Output = output.String()
+	report.DurationSeconds = time.Since(start).Seconds()
+	report.Error = errorString(err)
+	return err
+}
+
+// runModule runs "go mod tidy" and "go mod vendor" (if vendoring is enabled) for the provided module, writing the
+// output of the commands to stdout and recording the vendor decision, changes and verification failures in report.
+//
+// The go.mod and go.sum files of the module are copied to a temporary directory and "go mod tidy" is run using the
+// "-modfile" flag so that the copies are updated instead of the originals. The vendor directory is generated in a
+// temporary directory using the "-o" flag. If verify is true, every difference between the generated files and the
+// ones in the module is recorded as a failure. Otherwise, the generated files are recorded in update, which is written
+// to the module by runProject once the commands for every module have succeeded.
+func runModule(ctx context.Context, mod module, ws *workspace, verify bool, params Params, stdout io.Writer, report *ModuleReport, update *pendingUpdate) (rErr error) {
+	vendor, err := resolveVendorMode(ctx, params.executor(), mod.Dir, params.VendorMode)
+	if err != nil {
 		return err
 	}
-	if verify {
-		vendorDirExistsAfter := true
-		if _, err := os.Stat(vendorDirPath); os.IsNotExist(err) {
-			vendorDirExistsAfter = false
+	if vendor.Enabled && ws.contains(mod) {
+		vendor = VendorDecision{Enabled: false, Reason: "module is part of the go.work workspace, which is vendored using go work vendor"}
+	}
+	report.Vendor = vendor
+	_, _ = fmt.Fprintln(stdout, vendor.String())
+
+	scratchDir, err := os.MkdirTemp("", "godel-mod-plugin-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary directory")
+	}
+	defer func() {
+		if err := os.RemoveAll(scratchDir); err != nil && rErr == nil {
+			rErr = errors.Wrapf(err, "failed to remove temporary directory %s", scratchDir)
 		}
+	}()
 
-		if vendorDirExistsBefore != vendorDirExistsAfter {
-			if vendorDirExistsBefore {
-				return errors.Errorf("vendor directory existed before verify but did not exist after")
+	goModBefore, goSumBefore, err := readModFiles(mod.Dir)
+	if err != nil {
+		return err
+	}
+	// the toolchain directive is set before running "go mod tidy" so that the go.mod file is tidied as it will be
+	// written
+	goModInput := goModBefore
+	if params.Toolchain.Directive != "" {
+		var description string
+		if goModInput, description, err = setToolchainDirective(mod.relPath("go.mod"), goModBefore, params.Toolchain.Directive); err != nil {
+			return err
+		}
+		if verify {
+			var failure string
+			if description != "" {
+				failure = fmt.Sprintf("%s: %s", mod.relPath("go.mod"), description)
 			}
-			return errors.Errorf("vendor directory did not exist before verify but exists after")
+			report.addCheck(checkToolchainDirective, failure)
 		}
+	}
+	if err := writeModFiles(scratchDir, goModInput, goSumBefore); err != nil {
+		return err
+	}
+	scratchGoModPath := filepath.Join(scratchDir, "go.mod")
 
-		// only compare checksums if vendor directory exists before and after (other case is that vendor directory
-		// didn't exist before or after, in which case they are equal)
-		if vendorDirExistsBefore && vendorDirExistsAfter {
-			vendorChecksumsAfter, err := dirchecksum.ChecksumsForMatchingPaths(vendorDirPath, nil)
+	// the replace directives are checked, the vendor/modules.txt file is checked against go.mod and the vendored files
+	// are checked against go.sum before running "go mod tidy" because the checks do not require running it, so problems
+	// are reported even if a later command fails
+	if verify && params.Policy.Replace.enabled() {
+		if err := addReplacePolicyCheck(mod, goModBefore, params.Policy.Replace, report); err != nil {
+			return err
+		}
+	}
+	var modulesTxtInconsistent bool
+	if verify && vendor.Enabled && !params.Verify.SkipVendor {
+		vendored, err := readModulesTxt(filepath.Join(mod.Dir, "vendor"))
+		if err != nil {
+			return err
+		}
+		if vendored != nil {
+			failure, err := modulesTxtFailure(mod, goModBefore, vendored)
 			if err != nil {
-				return errors.Wrapf(err, "failed to compute checksums for %s", vendorDirPath)
+				return err
 			}
-			checksumDiff := vendorChecksumBefore.Diff(vendorChecksumsAfter)
-			if len(checksumDiff.Diffs) > 0 {
-				return errors.Errorf("vendor directory modified:\n%s", checksumDiff.String())
+			report.addCheck(checkModulesTxt, failure)
+			modulesTxtInconsistent = failure != ""
+			if !modulesTxtInconsistent {
+				if err := addVendorHashCheck(ctx, params.executor(), mod, goSumBefore, vendored, stdout, report); err != nil {
+					return err
+				}
 			}
 		}
 	}
+
+	if err := run(ctx, params.executor(), mod.Dir, moduleEnv, stdout, append([]string{"mod", "tidy", "-modfile=" + scratchGoModPath}, params.Tidy.args()...)...); err != nil {
+		return err
+	}
+	goModAfter, goSumAfter, err := readModFiles(scratchDir)
+	if err != nil {
+		return err
+	}
+	if report.GoVersion, err = parseGoDirective(mod.relPath("go.mod"), goModAfter); err != nil {
+		return err
+	}
+	goModChange := contentChange(mod.relPath("go.mod"), goModBefore, goModAfter)
+	goSumChange := contentChange(mod.relPath("go.sum"), goSumBefore, goSumAfter)
+	for _, change := range []*FileChange{goModChange, goSumChange} {
+		if change != nil {
+			report.Changes = append(report.Changes, *change)
+		}
+	}
+
+	if verify {
+		preTidyFailures := len(report.Failures)
+		report.addCheck(checkGoMod, modifiedFailure(goModChange))
+		if params.Verify.SkipGoSum {
+			report.skipCheck(checkGoSum, "verify.skip-go-sum is true")
+		} else {
+			report.addCheck(checkGoSum, modifiedFailure(goSumChange))
+		}
+		tidyFailed := len(report.Failures) > preTidyFailures
+		if err := checkPolicies(ctx, params.executor(), mod, scratchGoModPath, goModBefore, goModAfter, params.Policy, stdout, report); err != nil {
+			return err
+		}
+		switch {
+		case modulesTxtInconsistent:
+			report.skipCheck(checkVendor, "vendor/modules.txt is inconsistent with go.mod")
+			return nil
+		case tidyFailed:
+			// vendor directory is generated from go.mod, so only verify it once go.mod and go.sum are up-to-date
+			report.skipCheck(checkVendor, "go.mod or go.sum is not up-to-date")
+			return nil
+		case !vendor.Enabled:
+			report.skipCheck(checkVendor, vendor.String())
+			return nil
+		case params.Verify.SkipVendor:
+			report.skipCheck(checkVendor, "verify.skip-vendor is true")
+			return nil
+		}
+		scratchVendorDirPath := filepath.Join(scratchDir, "vendor")
+		if err := run(ctx, params.executor(), mod.Dir, moduleEnv, stdout, "mod", "vendor", "-modfile="+scratchGoModPath, "-o", scratchVendorDirPath); err != nil {
+			return err
+		}
+		change, err := vendorDirChange(mod.relPath("vendor"), filepath.Join(mod.Dir, "vendor"), scratchVendorDirPath)
+		if err != nil {
+			return err
+		}
+		if change == nil {
+			report.addCheck(checkVendor, "")
+			return nil
+		}
+		report.Changes = append(report.Changes, *change)
+		report.addCheck(checkVendor, vendorFailure(*change, params.Debug))
+		return nil
+	}
+
+	if vendor.Enabled {
+		// generate the vendor directory next to the existing one so that it can be moved into place with a rename
+		newVendorDirPath, err := os.MkdirTemp(mod.Dir, ".vendor-")
+		if err != nil {
+			return errors.Wrapf(err, "failed to create temporary directory")
+		}
+		update.newVendorDirPath, update.vendorDirPath = newVendorDirPath, filepath.Join(mod.Dir, "vendor")
+		if err := run(ctx, params.executor(), mod.Dir, moduleEnv, stdout, "mod", "vendor", "-modfile="+scratchGoModPath, "-o", newVendorDirPath); err != nil {
+			return err
+		}
+		change, err := vendorDirChange(mod.relPath("vendor"), filepath.Join(mod.Dir, "vendor"), newVendorDirPath)
+		if err != nil {
+			return err
+		}
+		if change != nil {
+			report.Changes = append(report.Changes, *change)
+		}
+	}
+
+	update.files = []fileChange{
+		{path: filepath.Join(mod.Dir, "go.mod"), before: goModBefore, after: goModAfter},
+		{path: filepath.Join(mod.Dir, "go.sum"), before: goSumBefore, after: goSumAfter},
+	}
 	return nil
 }
 
-func goModChecksums(projectDir string) (goModChecksum, goSumChecksum [32]byte, err error) {
-	goModChecksum, err = fileChecksum(path.Join(projectDir, "go.mod"))
+// contentChange returns the change from before to after for the file at relPath, where nil content represents a file
+// that does not exist. Returns nil if the content is equal.
+func contentChange(relPath string, before, after []byte) *FileChange {
+	diff := unifiedDiff(relPath, before, after)
+	if diff == "" {
+		return nil
+	}
+	change := &FileChange{
+		Path: relPath,
+		Type: FileModified,
+		Diff: diff,
+	}
+	switch {
+	case before == nil:
+		change.Type = FileCreated
+	case after == nil:
+		change.Type = FileRemoved
+	}
+	return change
+}
+
+// modifiedFailure returns the verification failure message for a change to a go.mod or go.sum file. Returns the empty
+// string if change is nil.
+func modifiedFailure(change *FileChange) string {
+	if change == nil {
+		return ""
+	}
+	return fileFailure(*change, "modified")
+}
+
+// fileFailure returns the verification failure message for a change to a file, which includes the diff of the change.
+// The description is used to describe the change (for example, "modified by go work sync").
+func fileFailure(change FileChange, description string) string {
+	return fmt.Sprintf("%s %s:\n%s", change.Path, description, strings.TrimSuffix(change.Diff, "\n"))
+}
+
+// dirChange returns the change from the directory at dirPath to the one generated at generatedDirPath, where a
+// directory that does not exist is treated as removed (or created). relPath is the path to the directory relative to
+// the project directory, which is recorded in the change. Returns nil if the directories are equal.
+func dirChange(relPath, dirPath, generatedDirPath string) (*FileChange, error) {
+	dirExistsBefore := dirExists(dirPath)
+	dirExistsAfter := dirExists(generatedDirPath)
+	if dirExistsBefore != dirExistsAfter {
+		if dirExistsBefore {
+			return &FileChange{Path: relPath, Type: FileRemoved}, nil
+		}
+		return &FileChange{Path: relPath, Type: FileCreated}, nil
+	}
+
+	// only compare checksums if directory exists before and after (other case is that directory didn't exist before or
+	// after, in which case they are equal)
+	if !dirExistsBefore {
+		return nil, nil
+	}
+	checksumsBefore, err := dirchecksum.ChecksumsForMatchingPaths(dirPath, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute checksums for %s", dirPath)
+	}
+	checksumsAfter, err := dirchecksum.ChecksumsForMatchingPaths(generatedDirPath, nil)
 	if err != nil {
-		return goModChecksum, goSumChecksum, err
+		return nil, errors.Wrapf(err, "failed to compute checksums for %s", generatedDirPath)
 	}
-	goSumPath := path.Join(projectDir, "go.sum")
-	if _, err := os.Stat(goSumPath); os.IsNotExist(err) {
-		// if go.sum file does not exist, return default value for checksum of go.sum
-		return goModChecksum, goSumChecksum, nil
+	checksumDiff := checksumsBefore.Diff(checksumsAfter)
+	if len(checksumDiff.Diffs) == 0 {
+		return nil, nil
 	}
-	goSumChecksum, err = fileChecksum(goSumPath)
+	return &FileChange{
+		Path:          relPath,
+		Type:          FileModified,
+		ChecksumDiffs: checksumDiff.Diffs,
+	}, nil
+}
+
+// vendorDirChange returns the change from the vendor directory at vendorDirPath to the one generated at
+// generatedVendorDirPath. If the directory was modified, the differences are grouped by the module that provides the
+// files using the modules.txt files of the directories. Returns nil if the directories are equal.
+func vendorDirChange(relPath, vendorDirPath, generatedVendorDirPath string) (*FileChange, error) {
+	change, err := dirChange(relPath, vendorDirPath, generatedVendorDirPath)
+	if err != nil || change == nil || change.Type != FileModified {
+		return change, err
+	}
+	modulesBefore, err := readModulesTxt(vendorDirPath)
 	if err != nil {
-		return goModChecksum, goSumChecksum, err
+		return nil, err
+	}
+	modulesAfter, err := readModulesTxt(generatedVendorDirPath)
+	if err != nil {
+		return nil, err
+	}
+	change.Modules, change.OtherFiles = vendorDrift(modulesBefore, modulesAfter, change.ChecksumDiffs)
+	return change, nil
+}
+
+// vendorFailure returns the verification failure message for a change to a vendor directory. If debug is true, the
+// message for a modified directory includes every file that differs.
+func vendorFailure(change FileChange, debug bool) string {
+	switch change.Type {
+	case FileRemoved:
+		return fmt.Sprintf("%s directory exists but would be removed by vendoring", change.Path)
+	case FileCreated:
+		return fmt.Sprintf("%s directory does not exist but would be created by vendoring", change.Path)
+	default:
+		return fmt.Sprintf("%s directory modified:\n%s", change.Path, vendorDriftSummary(change, debug))
 	}
-	return goModChecksum, goSumChecksum, nil
 }
 
-func fileChecksum(fpath string) ([32]byte, error) {
-	fBytes, err := os.ReadFile(fpath)
+func dirExists(dirPath string) bool {
+	fi, err := os.Stat(dirPath)
+	return err == nil && fi.IsDir()
+}
+
+// readModFiles returns the content of the go.mod and go.sum files in dir. If the go.sum file does not exist, its
+// content is returned as nil.
+func readModFiles(dir string) (goMod, goSum []byte, err error) {
+	goModPath := filepath.Join(dir, "go.mod")
+	goMod, err = os.ReadFile(goModPath)
 	if err != nil {
-		return [32]byte{}, errors.Wrapf(err, "failed to read %s", fpath)
+		return nil, nil, errors.Wrapf(err, "failed to read %s", goModPath)
+	}
+	goSumPath := filepath.Join(dir, "go.sum")
+	goSum, err = os.ReadFile(goSumPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, errors.Wrapf(err, "failed to read %s", goSumPath)
 	}
-	return sha256.Sum256(fBytes), nil
+	return goMod, goSum, nil
 }
 
-// modVendorGoFlagsSet returns true if the GOFLAGS environment variable contains the value "-mod=vendor".
-func modVendorGoFlagsSet() bool {
-	return slices.Contains(strings.Fields(os.Getenv("GOFLAGS")), "-mod=vendor")
+// writeModFiles writes the provided content to the go.mod and go.sum files in dir. If goSum is nil, the go.sum file is
+// not written.
+func writeModFiles(dir string, goMod, goSum []byte) error {
+	goModPath := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(goModPath, goMod, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write %s", goModPath)
+	}
+	if goSum == nil {
+		return nil
+	}
+	goSumPath := filepath.Join(dir, "go.sum")
+	if err := os.WriteFile(goSumPath, goSum, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write %s", goSumPath)
+	}
+	return nil
 }
 
-func run(stdout io.Writer, args ...string) error {
-	cmd := exec.Command("go", append([]string{"mod"}, args...)...)
-	cmd.Stdout = stdout
-	cmd.Stderr = stdout
-	if err := cmd.Run(); err != nil {
-		if _, ok := err.(*exec.ExitError); !ok {
-			// if error is not an exit error, wrap it
-			return errors.Wrapf(err, "failed to execute command %v", cmd.Args)
+// replaceDir replaces the directory at dst with the directory at src. If src does not exist, dst is removed. The
+// directories must be on the same file system.
+func replaceDir(src, dst string) error {
+	var backupDir string
+	if dirExists(dst) {
+		var err error
+		backupDir, err = os.MkdirTemp(filepath.Dir(dst), "."+filepath.Base(dst)+"-backup-")
+		if err != nil {
+			return errors.Wrapf(err, "failed to create temporary directory")
+		}
+		backupPath := filepath.Join(backupDir, filepath.Base(dst))
+		if err := os.Rename(dst, backupPath); err != nil {
+			_ = os.RemoveAll(backupDir)
+			return errors.Wrapf(err, "failed to move %s to %s", dst, backupPath)
+		}
+		if dirExists(src) {
+			if err := os.Rename(src, dst); err != nil {
+				// restore the original directory
+				_ = os.Rename(backupPath, dst)
+				_ = os.RemoveAll(backupDir)
+				return errors.Wrapf(err, "failed to move %s to %s", src, dst)
+			}
+		}
+		if err := os.RemoveAll(backupDir); err != nil {
+			return errors.Wrapf(err, "failed to remove %s", backupDir)
 		}
-		// otherwise, return empty error
-		return fmt.Errorf("")
+		return nil
+	}
+	if !dirExists(src) {
+		return nil
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return errors.Wrapf(err, "failed to move %s to %s", src, dst)
 	}
 	return nil
 }
+
+// moduleEnv is the environment used for commands that operate on a single module. Workspace mode is disabled so that
+// the commands behave the same regardless of whether the module is part of a go.work workspace.
+var moduleEnv = []string{"GOWORK=off"}
+
+// run runs the go command with the provided arguments in dir using executor. The provided environment variables are
+// added to the environment of the current process. The output that the command writes to stderr is captured: if the
+// command succeeds, it is written to stdout after the command completes, and if the command exits with a non-zero exit
+// code, it is returned as part of a *CommandError.
+func run(ctx context.Context, executor Executor, dir string, env []string, stdout io.Writer, args ...string) error {
+	stderr, err := execute(ctx, executor, dir, env, stdout, args...)
+	if err != nil {
+		return err
+	}
+	_, _ = stdout.Write(stderr)
+	return nil
+}
+
+// execute runs the go command with the provided arguments in dir using executor, writing its standard output to
+// stdout, and returns the output that the command wrote to stderr. If the command exits with a non-zero exit code, the
+// returned error is a *CommandError. The command is killed if the provided context is done before the command
+// completes.
+func execute(ctx context.Context, executor Executor, dir string, env []string, stdout io.Writer, args ...string) ([]byte, error) {
+	cmdArgs := append([]string{"go"}, args...)
+	stderr := &bytes.Buffer{}
+	if err := executor.Run(ctx, Command{
+		Dir:    dir,
+		Args:   args,
+		Env:    env,
+		Stdout: stdout,
+		Stderr: stderr,
+	}); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, errors.Wrapf(ctxErr, "command %v did not complete", cmdArgs)
+		}
+		var exitErr exitCoder
+		if !errors.As(err, &exitErr) {
+			// if error is not an exit error, wrap it
+			return nil, errors.Wrapf(err, "failed to execute command %v", cmdArgs)
+		}
+		return nil, &CommandError{
+			Args:     cmdArgs,
+			Dir:      dir,
+			ExitCode: exitErr.ExitCode(),
+			Stderr:   stderr.String(),
+		}
+	}
+	return stderr.Bytes(), nil
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCancelledContextDoesNotModifyModule(t *testing.T) {
+	projectDir := t.TempDir()
+	goMod := "module github.com/mod/test\n\ngo 1.22\n\nrequire github.com/pkg/errors v0.9.1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte(goMod), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "vendor"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "vendor", "modules.txt"), []byte("# github.com/pkg/errors v0.9.1\n"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := Run(ctx, projectDir, false, Params{VendorMode: VendorModeAlways}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled), "unexpected error: %v", err)
+
+	gotGoMod, err := os.ReadFile(filepath.Join(projectDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, goMod, string(gotGoMod))
+	gotModulesTxt, err := os.ReadFile(filepath.Join(projectDir, "vendor", "modules.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "# github.com/pkg/errors v0.9.1\n", string(gotModulesTxt))
+
+	entries, err := os.ReadDir(projectDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"go.mod", "vendor"}, names)
+}
+
+// failDirExecutor is an Executor that fails every command run in dir.
+type failDirExecutor struct {
+	Executor
+	dir string
+}
+
+func (e failDirExecutor) Run(ctx context.Context, cmd Command) error {
+	if cmd.Dir == e.dir {
+		return fakeExitError(1)
+	}
+	return e.Executor.Run(ctx, cmd)
+}
+
+func TestRunFailedModuleDoesNotModifyOtherModules(t *testing.T) {
+	projectDir := newTestModule(t, true)
+	toolsDir := filepath.Join(projectDir, "tools")
+	writeTestFiles(toolsDir, map[string]string{
+		"go.mod": "module github.com/mod/test/tools\n\ngo 1.22\n",
+	})
+	toolchain := newUpToDateToolchain()
+	toolchain.tidyGoMod = "module github.com/mod/test\n\ngo 1.22\n"
+
+	err := Run(context.Background(), projectDir, false, Params{
+		VendorMode: VendorModeAlways,
+		Executor:   failDirExecutor{Executor: toolchain, dir: toolsDir},
+	}, &bytes.Buffer{})
+	require.Error(t, err)
+	var modErr *ModuleError
+	require.True(t, errors.As(err, &modErr), "unexpected error type %T", err)
+	assert.Equal(t, "github.com/mod/test/tools", modErr.Module)
+
+	// the root module succeeded, but it is not modified because another module failed
+	gotGoMod, err := os.ReadFile(filepath.Join(projectDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, testGoMod, string(gotGoMod))
+	entries, err := os.ReadDir(projectDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"go.mod", "go.sum", "tools", "vendor"}, names)
+}
+
+func TestReplaceDir(t *testing.T) {
+	for i, tc := range []struct {
+		name      string
+		src       map[string]string
+		dst       map[string]string
+		wantFiles map[string]string
+	}{
+		{
+			name:      "replaces existing directory",
+			src:       map[string]string{"modules.txt": "new"},
+			dst:       map[string]string{"modules.txt": "old", "removed.go": "old"},
+			wantFiles: map[string]string{"modules.txt": "new"},
+		},
+		{
+			name:      "creates directory",
+			src:       map[string]string{"modules.txt": "new"},
+			wantFiles: map[string]string{"modules.txt": "new"},
+		},
+		{
+			name: "removes directory if source does not exist",
+			dst:  map[string]string{"modules.txt": "old"},
+		},
+	} {
+		dir := t.TempDir()
+		src, dst := filepath.Join(dir, ".vendor-new"), filepath.Join(dir, "vendor")
+		writeTestFiles(src, tc.src)
+		writeTestFiles(dst, tc.dst)
+
+		require.NoError(t, replaceDir(src, dst), "Case %d: %s", i, tc.name)
+
+		assert.False(t, dirExists(src), "Case %d: %s", i, tc.name)
+		if tc.wantFiles == nil {
+			assert.False(t, dirExists(dst), "Case %d: %s", i, tc.name)
+		} else {
+			gotFiles := make(map[string]string)
+			entries, err := os.ReadDir(dst)
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			for _, entry := range entries {
+				content, err := os.ReadFile(filepath.Join(dst, entry.Name()))
+				require.NoError(t, err, "Case %d: %s", i, tc.name)
+				gotFiles[entry.Name()] = string(content)
+			}
+			assert.Equal(t, tc.wantFiles, gotFiles, "Case %d: %s", i, tc.name)
+		}
+
+		// no backup directories should remain
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err, "Case %d: %s", i, tc.name)
+		assert.LessOrEqual(t, len(entries), 1, "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestWriteUpdatesRollsBackOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	modDir := filepath.Join(dir, "mod")
+	writeTestFiles(modDir, map[string]string{
+		"go.mod":             "module github.com/mod/test\n",
+		"vendor/modules.txt": "old\n",
+	})
+	newVendorDirPath := filepath.Join(modDir, ".vendor-new")
+	writeTestFiles(newVendorDirPath, map[string]string{"modules.txt": "new\n"})
+
+	updates := []pendingUpdate{
+		{
+			files: []fileChange{
+				{path: filepath.Join(modDir, "go.mod"), after: []byte("module github.com/mod/test\n\ngo 1.22\n")},
+				{path: filepath.Join(modDir, "go.sum"), after: []byte("github.com/pkg/errors v0.9.1 h1:hash=\n")},
+			},
+			newVendorDirPath: newVendorDirPath,
+			vendorDirPath:    filepath.Join(modDir, "vendor"),
+		},
+		{
+			// writing fails because the directory does not exist
+			files: []fileChange{{path: filepath.Join(dir, "missing", "go.mod"), after: []byte("module github.com/mod/missing\n")}},
+		},
+	}
+	err := writeUpdates(updates)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "changes were rolled back")
+
+	content, err := os.ReadFile(filepath.Join(modDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, "module github.com/mod/test\n", string(content))
+	_, err = os.Stat(filepath.Join(modDir, "go.sum"))
+	assert.True(t, os.IsNotExist(err))
+	content, err = os.ReadFile(filepath.Join(modDir, "vendor", "modules.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "old\n", string(content))
+
+	// no backup or restore directories should remain
+	entries, err := os.ReadDir(modDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"go.mod", "vendor"}, names)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+)
+
+// requirementGraph is the module requirement graph as reported by "go mod graph". Every node is a module version in the
+// form "path@version" except for the main module, which is its path. The nodes for the go and toolchain versions
+// ("go@1.22" and "toolchain@go1.22.5") are omitted.
+type requirementGraph struct {
+	// root is the main module.
+	root string
+	// edges maps every node to the nodes it requires in the order in which they are reported.
+	edges map[string][]string
+}
+
+// readRequirementGraph returns the requirement graph of the module in dir.
+func readRequirementGraph(ctx context.Context, executor Executor, dir string) (*requirementGraph, error) {
+	output := &bytes.Buffer{}
+	if _, err := execute(ctx, executor, dir, moduleEnv, output, "mod", "graph"); err != nil {
+		return nil, err
+	}
+	return parseModGraph(output.Bytes()), nil
+}
+
+// parseModGraph parses the output of "go mod graph", which consists of a line of the form "<from> <to>" for every
+// requirement. The main module is the first node of the first line.
+func parseModGraph(output []byte) *requirementGraph {
+	graph := &requirementGraph{
+		edges: make(map[string][]string),
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if graph.root == "" {
+			graph.root = fields[0]
+		}
+		if isGoVersionNode(fields[0]) || isGoVersionNode(fields[1]) {
+			continue
+		}
+		graph.edges[fields[0]] = append(graph.edges[fields[0]], fields[1])
+	}
+	return graph
+}
+
+// isGoVersionNode returns true if the provided node of the requirement graph is a go or toolchain version.
+func isGoVersionNode(node string) bool {
+	return strings.HasPrefix(node, "go@") || strings.HasPrefix(node, "toolchain@")
+}
+
+// nodePath returns the module path of the provided node of the requirement graph.
+func nodePath(node string) string {
+	modPath, _, _ := strings.Cut(node, "@")
+	return modPath
+}
+
+// shortestChain returns the shortest chain of requirements from the main module to a version of the module with the
+// provided path, starting with the main module. If there are multiple chains of the same length, the one that follows
+// the requirements in the order in which they are reported is returned. Returns nil if the module is not in the graph.
+func (g *requirementGraph) shortestChain(modPath string) []string {
+	if g.root == "" {
+		return nil
+	}
+	parents := map[string]string{g.root: ""}
+	queue := []string{g.root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node != g.root && nodePath(node) == modPath {
+			var chain []string
+			for ; node != ""; node = parents[node] {
+				chain = append([]string{node}, chain...)
+			}
+			return chain
+		}
+		for _, next := range g.edges[node] {
+			if _, ok := parents[next]; !ok {
+				parents[next] = node
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testModGraph = `github.com/mod/test github.com/foo/bar@v1.0.0
+github.com/mod/test github.com/pkg/errors@v0.9.1
+github.com/mod/test go@1.22
+github.com/foo/bar@v1.0.0 github.com/foo/baz@v1.1.0
+github.com/foo/bar@v1.0.0 github.com/pkg/errors@v0.8.0
+github.com/foo/baz@v1.1.0 github.com/foo/qux@v0.1.0
+github.com/foo/baz@v1.1.0 go@1.21
+go@1.22 toolchain@go1.22.5
+`
+
+func TestParseModGraph(t *testing.T) {
+	graph := parseModGraph([]byte(testModGraph))
+	assert.Equal(t, "github.com/mod/test", graph.root)
+	assert.Equal(t, map[string][]string{
+		"github.com/mod/test":       {"github.com/foo/bar@v1.0.0", "github.com/pkg/errors@v0.9.1"},
+		"github.com/foo/bar@v1.0.0": {"github.com/foo/baz@v1.1.0", "github.com/pkg/errors@v0.8.0"},
+		"github.com/foo/baz@v1.1.0": {"github.com/foo/qux@v0.1.0"},
+	}, graph.edges)
+}
+
+func TestShortestChain(t *testing.T) {
+	graph := parseModGraph([]byte(testModGraph))
+	for i, tc := range []struct {
+		modPath string
+		want    []string
+	}{
+		{"github.com/pkg/errors", []string{"github.com/mod/test", "github.com/pkg/errors@v0.9.1"}},
+		{"github.com/foo/qux", []string{"github.com/mod/test", "github.com/foo/bar@v1.0.0", "github.com/foo/baz@v1.1.0", "github.com/foo/qux@v0.1.0"}},
+		{"github.com/mod/test", nil},
+		{"golang.org/x/mod", nil},
+	} {
+		assert.Equal(t, tc.want, graph.shortestChain(tc.modPath), "Case %d: %s", i, tc.modPath)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// moduleViolation is a module in the build list that violates the module policy.
+type moduleViolation struct {
+	Module buildListModule
+	Reason string
+}
+
+// modulePolicyViolations returns the modules in the provided build list that violate the policy. The main module is
+// never a violation. Deny rules are matched against both a module and its replacement (unless the replacement is a
+// local directory), while allow rules are only matched against the module itself.
+func modulePolicyViolations(policy ModulePolicyParams, buildList []buildListModule) []moduleViolation {
+	var violations []moduleViolation
+	for _, mod := range buildList {
+		if mod.Main {
+			continue
+		}
+		candidates := []buildListModule{mod}
+		if mod.Replace != nil && mod.Replace.Version != "" {
+			candidates = append(candidates, *mod.Replace)
+		}
+		if rule, candidate, ok := matchingRule(policy.Deny, candidates...); ok {
+			reason := fmt.Sprintf("%s is denied by the rule %q", moduleVersionString(candidate.Path, candidate.Version), rule.String())
+			if rule.Reason != "" {
+				reason += ": " + rule.Reason
+			}
+			violations = append(violations, moduleViolation{Module: mod, Reason: reason})
+			continue
+		}
+		if len(policy.Allow) > 0 {
+			if _, _, ok := matchingRule(policy.Allow, mod); !ok {
+				violations = append(violations, moduleViolation{Module: mod, Reason: fmt.Sprintf("%s does not match any allow rule", moduleVersionString(mod.Path, mod.Version))})
+			}
+		}
+	}
+	return violations
+}
+
+// matchingRule returns the first of the provided rules that matches any of the provided modules along with the
+// module that it matches.
+func matchingRule(rules []ModuleRule, modules ...buildListModule) (ModuleRule, buildListModule, bool) {
+	for _, rule := range rules {
+		for _, mod := range modules {
+			if rule.matches(mod.Path, mod.Version) {
+				return rule, mod, true
+			}
+		}
+	}
+	return ModuleRule{}, buildListModule{}, false
+}
+
+// addModulePolicyCheck records the result of checking the provided build list of the module against the module
+// policy in report. Each violation includes the require directive for the module in the go.mod file at goModPath
+// (whose content is goMod) and the import chain that pulls the module in.
+func addModulePolicyCheck(ctx context.Context, executor Executor, mod module, goModPath string, goMod []byte, buildList []buildListModule, policy ModulePolicyParams, report *ModuleReport) error {
+	violations := modulePolicyViolations(policy, buildList)
+	if len(violations) == 0 {
+		report.addCheck(checkModulePolicy, "")
+		return nil
+	}
+
+	var modPaths []string
+	for _, violation := range violations {
+		modPaths = append(modPaths, violation.Module.Path)
+	}
+	chains, err := importChains(ctx, executor, mod.Dir, goModPath, modPaths)
+	if err != nil {
+		return err
+	}
+	lines, err := requireLines(mod.relPath("go.mod"), goMod)
+	if err != nil {
+		return err
+	}
+
+	var descriptions []string
+	for _, violation := range violations {
+		line, ok := lines[violation.Module.Path]
+		if !ok {
+			line = fmt.Sprintf("%s does not require the module directly", mod.relPath("go.mod"))
+		}
+		chain := "import chain: not imported by any package of the module"
+		if packages := chains[violation.Module.Path]; len(packages) > 0 {
+			chain = "import chain: " + strings.Join(packages, " -> ")
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s\n    %s\n    %s", violation.Reason, line, chain))
+	}
+	report.addCheck(checkModulePolicy, fmt.Sprintf("%s violates the module policy:\n%s", mod.relPath("go.mod"), strings.Join(descriptions, "\n")))
+	return nil
+}
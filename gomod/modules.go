@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/palantir/pkg/matcher"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+)
+
+// module is a Go module within a project.
+type module struct {
+	// Dir is the path to the directory that contains the go.mod file of the module.
+	Dir string
+	// RelDir is the path to Dir relative to the project directory. It is "." for the module at the project root.
+	RelDir string
+	// Path is the module path declared in go.mod.
+	Path string
+}
+
+// relPath returns the path of the file with the provided name in the module directory relative to the project
+// directory.
+func (m module) relPath(name string) string {
+	return filepath.ToSlash(filepath.Join(m.RelDir, name))
+}
+
+// discoverModules returns all of the modules in projectDir, which are the directories that contain a go.mod file. The
+// directories named "vendor" or "testdata", the directories whose names start with "." or "_" (which are ignored by
+// the go command) and the paths matched by exclude are skipped. The module at the root of the project (if present) is
+// returned first and the others are sorted by their relative directory.
+func discoverModules(projectDir string, exclude matcher.Matcher) ([]module, error) {
+	var modules []module
+	if err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		relDir, err := filepath.Rel(projectDir, path)
+		if err != nil {
+			return err
+		}
+		if relDir != "." {
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if exclude != nil && exclude.Match(filepath.ToSlash(relDir)) {
+				return filepath.SkipDir
+			}
+		}
+		goModPath := filepath.Join(path, "go.mod")
+		if _, err := os.Stat(goModPath); err != nil {
+			return nil
+		}
+		modPath, err := modulePath(goModPath)
+		if err != nil {
+			return err
+		}
+		modules = append(modules, module{
+			Dir:    path,
+			RelDir: relDir,
+			Path:   modPath,
+		})
+		return nil
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to find modules in %s", projectDir)
+	}
+	if len(modules) == 0 {
+		return nil, errors.Errorf("no go.mod files found in %s", projectDir)
+	}
+	sort.SliceStable(modules, func(i, j int) bool {
+		if modules[i].RelDir == "." || modules[j].RelDir == "." {
+			return modules[i].RelDir == "."
+		}
+		return modules[i].RelDir < modules[j].RelDir
+	})
+	return modules, nil
+}
+
+func modulePath(goModPath string) (string, error) {
+	goModBytes, err := os.ReadFile(goModPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %s", goModPath)
+	}
+	modPath := modfile.ModulePath(goModBytes)
+	if modPath == "" {
+		return "", errors.Errorf("%s does not declare a module path", goModPath)
+	}
+	return modPath, nil
+}
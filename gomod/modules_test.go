@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/pkg/matcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverModules(t *testing.T) {
+	projectDir := t.TempDir()
+	for relDir, modPath := range map[string]string{
+		".":              "github.com/mod/test",
+		"tools":          "github.com/mod/test/tools",
+		"sdk/go":         "github.com/mod/test/sdk",
+		"examples/basic": "github.com/mod/test/examples/basic",
+		"vendor/foo":     "github.com/foo",
+		"testdata/bar":   "github.com/bar",
+		".hidden":        "github.com/hidden",
+		"_ignored":       "github.com/ignored",
+	} {
+		dir := filepath.Join(projectDir, relDir)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+modPath+"\n"), 0644))
+	}
+
+	modules, err := discoverModules(projectDir, matcher.Path("examples"))
+	require.NoError(t, err)
+
+	var got []string
+	for _, mod := range modules {
+		got = append(got, mod.RelDir+"="+mod.Path)
+	}
+	assert.Equal(t, []string{
+		".=github.com/mod/test",
+		"sdk/go=github.com/mod/test/sdk",
+		"tools=github.com/mod/test/tools",
+	}, got)
+}
+
+func TestDiscoverModulesNoModules(t *testing.T) {
+	projectDir := t.TempDir()
+	_, err := discoverModules(projectDir, nil)
+	assert.EqualError(t, err, "no go.mod files found in "+projectDir)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// vendoredModule is a module entry in a vendor/modules.txt file.
+type vendoredModule struct {
+	// Path and Version are the path and version of the module. Version is empty for entries that only record a
+	// replacement of all versions of a module.
+	Path    string
+	Version string
+	// ReplacePath and ReplaceVersion are the replacement of the module, if any. ReplaceVersion is empty for filesystem
+	// replacements.
+	ReplacePath    string
+	ReplaceVersion string
+	// Explicit is true if the module is required explicitly by go.mod.
+	Explicit bool
+	// GoVersion is the value of the go directive of the module, if known.
+	GoVersion string
+	// Packages are the import paths of the vendored packages that are provided by the module.
+	Packages []string
+}
+
+// String returns the version of the module followed by its replacement, if any (for example,
+// "v1.0.0 => github.com/fork/mod v1.0.1").
+func (m vendoredModule) String() string {
+	s := m.Version
+	if m.ReplacePath != "" {
+		s = strings.TrimSpace(strings.Join([]string{s, "=>", m.ReplacePath, m.ReplaceVersion}, " "))
+	}
+	return s
+}
+
+// readModulesTxt returns the modules listed in the modules.txt file in vendorDir. Returns nil if the file does not
+// exist.
+func readModulesTxt(vendorDir string) ([]vendoredModule, error) {
+	modulesTxtPath := filepath.Join(vendorDir, "modules.txt")
+	content, err := os.ReadFile(modulesTxtPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", modulesTxtPath)
+	}
+	return parseModulesTxt(content), nil
+}
+
+// parseModulesTxt parses the content of a vendor/modules.txt file. Lines of the form "# <module> <version>" (optionally
+// followed by "=> <replacement> [<version>]") start a module entry, lines starting with "## " contain annotations for
+// the current module and all other non-empty lines are packages provided by the current module. Malformed lines are
+// ignored.
+func parseModulesTxt(content []byte) []vendoredModule {
+	var modules []vendoredModule
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "## "):
+			if len(modules) == 0 {
+				continue
+			}
+			current := &modules[len(modules)-1]
+			for _, annotation := range strings.Split(strings.TrimPrefix(line, "## "), ";") {
+				annotation = strings.TrimSpace(annotation)
+				if annotation == "explicit" {
+					current.Explicit = true
+				} else if goVersion, ok := strings.CutPrefix(annotation, "go "); ok {
+					current.GoVersion = goVersion
+				}
+			}
+		case strings.HasPrefix(line, "# "):
+			fields := strings.Fields(strings.TrimPrefix(line, "# "))
+			var mod vendoredModule
+			left, right := fields, []string(nil)
+			for i, field := range fields {
+				if field == "=>" {
+					left, right = fields[:i], fields[i+1:]
+					break
+				}
+			}
+			if len(left) == 0 || len(left) > 2 || len(right) > 2 {
+				continue
+			}
+			mod.Path = left[0]
+			if len(left) == 2 {
+				mod.Version = left[1]
+			}
+			if len(right) > 0 {
+				mod.ReplacePath = right[0]
+			}
+			if len(right) == 2 {
+				mod.ReplaceVersion = right[1]
+			}
+			modules = append(modules, mod)
+		default:
+			if len(modules) > 0 && !strings.HasPrefix(line, "#") {
+				current := &modules[len(modules)-1]
+				current.Packages = append(current.Packages, line)
+			}
+		}
+	}
+	return modules
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseModulesTxt(t *testing.T) {
+	got := parseModulesTxt([]byte(`# github.com/pkg/errors v0.9.1
+## explicit
+github.com/pkg/errors
+# golang.org/x/mod v0.40.0
+## explicit; go 1.24.0
+golang.org/x/mod/modfile
+golang.org/x/mod/module
+# github.com/palantir/pkg v1.0.0 => github.com/fork/pkg v1.0.1
+## go 1.21
+github.com/palantir/pkg/matcher
+# github.com/local/mod v0.0.0 => ./local
+## explicit
+# github.com/all/versions => github.com/fork/versions v1.2.3
+## explicit
+`))
+	assert.Equal(t, []vendoredModule{
+		{Path: "github.com/pkg/errors", Version: "v0.9.1", Explicit: true, Packages: []string{"github.com/pkg/errors"}},
+		{Path: "golang.org/x/mod", Version: "v0.40.0", Explicit: true, GoVersion: "1.24.0", Packages: []string{"golang.org/x/mod/modfile", "golang.org/x/mod/module"}},
+		{Path: "github.com/palantir/pkg", Version: "v1.0.0", ReplacePath: "github.com/fork/pkg", ReplaceVersion: "v1.0.1", GoVersion: "1.21", Packages: []string{"github.com/palantir/pkg/matcher"}},
+		{Path: "github.com/local/mod", Version: "v0.0.0", ReplacePath: "./local", Explicit: true},
+		{Path: "github.com/all/versions", ReplacePath: "github.com/fork/versions", ReplaceVersion: "v1.2.3", Explicit: true},
+	}, got)
+
+	assert.Equal(t, "v1.0.0 => github.com/fork/pkg v1.0.1", got[2].String())
+	assert.Equal(t, "v0.0.0 => ./local", got[3].String())
+}
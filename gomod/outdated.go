@@ -0,0 +1,264 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	modmodule "golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// UpdateKind is the kind of the newest update with the same module path that is available for a module. Newer major
+// versions have a different module path (such as "example.com/mod/v2") and are reported separately.
+type UpdateKind string
+
+const (
+	// UpdatePatch is a newer patch version with the same major and minor version.
+	UpdatePatch UpdateKind = "patch"
+	// UpdateMinor is a newer minor version with the same major version. For a module with major version 0, any newer
+	// v0 version with a different minor version (such as v0.10.0 for v0.9.1) is a minor update.
+	UpdateMinor UpdateKind = "minor"
+)
+
+// OutdatedReport lists the requirements of every module in a project for which newer versions are available.
+type OutdatedReport struct {
+	// ProjectDir is the absolute path to the project directory.
+	ProjectDir string `json:"projectDir"`
+	// Modules are the results for the modules in the project.
+	Modules []OutdatedModuleReport `json:"modules"`
+}
+
+// OutdatedModuleReport lists the requirements of a single module for which newer versions are available.
+type OutdatedModuleReport struct {
+	// Path is the module path.
+	Path string `json:"path"`
+	// Dir is the directory of the module relative to the project directory.
+	Dir string `json:"dir"`
+	// Requirements are the outdated modules in the build list of the module, sorted by module path.
+	Requirements []OutdatedRequirement `json:"requirements"`
+}
+
+// OutdatedRequirement is a module in the build list for which a newer version is available.
+type OutdatedRequirement struct {
+	// Path is the module path.
+	Path string `json:"path"`
+	// Version is the version in the build list.
+	Version string `json:"version"`
+	// Direct is true if the module is required directly by the go.mod file (rather than marked as "// indirect").
+	Direct bool `json:"direct"`
+	// Update is the kind of the update to LatestCompatible. It is empty if there is no newer version with the same
+	// module path.
+	Update UpdateKind `json:"update,omitempty"`
+	// LatestCompatible is the latest version with the same module path, if it is newer than Version.
+	LatestCompatible string `json:"latestCompatible,omitempty"`
+	// LatestMajor is the path and version of the latest version with a newer major version (for example,
+	// "example.com/mod/v3@v3.1.0"), if any.
+	LatestMajor string `json:"latestMajor,omitempty"`
+}
+
+// Outdated returns the requirements of every module in projectDir for which newer versions are available, as reported
+// by "go list -m -u -json all". Newer major versions are found by querying the latest version of the module paths with
+// the following major version suffixes. The versions are resolved using the module proxy configured by GOPROXY (which
+// may be a "file://" URL). Modules that are replaced by a replace directive are not reported. If directOnly is true,
+// only the modules that are required directly are reported.
+func Outdated(ctx context.Context, projectDir string, directOnly bool, params Params) (*OutdatedReport, error) {
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine absolute path of project directory")
+	}
+	modules, err := discoverModules(projectDir, params.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	if env := toolchainEnv(false, params.Toolchain); len(env) > 0 {
+		params.Executor = envExecutor{Executor: params.executor(), env: env}
+	}
+
+	report := &OutdatedReport{
+		ProjectDir: projectDir,
+		Modules:    make([]OutdatedModuleReport, len(modules)),
+	}
+	moduleErrs := make([]error, len(modules))
+	forEachParallel(len(modules), params.Concurrency, func(i int) {
+		report.Modules[i] = OutdatedModuleReport{
+			Path: modules[i].Path,
+			Dir:  modules[i].RelDir,
+		}
+		report.Modules[i].Requirements, moduleErrs[i] = outdatedModule(ctx, params.executor(), modules[i], directOnly)
+	})
+	var errs []error
+	for i, mod := range modules {
+		if moduleErrs[i] != nil {
+			errs = append(errs, &ModuleError{Module: mod.Path, Err: moduleErrs[i]})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, joinErrors(errs)
+	}
+	return report, nil
+}
+
+// outdatedModule returns the outdated requirements of the provided module. The "-mod=readonly" flag is used so that
+// the build list is determined from go.mod even if the module is vendored.
+func outdatedModule(ctx context.Context, executor Executor, mod module, directOnly bool) ([]OutdatedRequirement, error) {
+	buildList, err := listModules(ctx, executor, mod.Dir, "-mod=readonly", "-u", "all")
+	if err != nil {
+		return nil, err
+	}
+	var candidates []buildListModule
+	for _, dep := range buildList {
+		if !dep.Main && (!directOnly || !dep.Indirect) && dep.Replace == nil {
+			candidates = append(candidates, dep)
+		}
+	}
+	latestMajors, err := latestMajorVersions(ctx, executor, mod.Dir, candidates)
+	if err != nil {
+		return nil, err
+	}
+	return outdatedRequirements(candidates, latestMajors), nil
+}
+
+// outdatedRequirements returns the modules in the provided build list for which a newer version with the same module
+// path or a newer major version is available, where latestMajors maps a module path to the path and version of its
+// latest newer major version. Both are reported independently, so a module that has both is reported with both.
+func outdatedRequirements(buildList []buildListModule, latestMajors map[string]string) []OutdatedRequirement {
+	var requirements []OutdatedRequirement
+	for _, dep := range buildList {
+		req := OutdatedRequirement{
+			Path:        dep.Path,
+			Version:     dep.Version,
+			Direct:      !dep.Indirect,
+			LatestMajor: latestMajors[dep.Path],
+		}
+		if dep.Update != nil && semver.Compare(dep.Update.Version, dep.Version) > 0 {
+			req.LatestCompatible = dep.Update.Version
+		}
+		switch {
+		case req.LatestCompatible == "":
+		case semver.MajorMinor(req.LatestCompatible) == semver.MajorMinor(dep.Version):
+			req.Update = UpdatePatch
+		default:
+			req.Update = UpdateMinor
+		}
+		if req.LatestCompatible != "" || req.LatestMajor != "" {
+			requirements = append(requirements, req)
+		}
+	}
+	return requirements
+}
+
+// latestMajorVersions returns the path and version of the latest newer major version of every provided module that
+// has one. The module paths with the following major version suffixes are queried in rounds using
+// "go list -m -e -json <path>@latest" until no newer major version is found. The query of a module path that does not
+// exist fails, which is reported as the Error of the module by "go list -e".
+func latestMajorVersions(ctx context.Context, executor Executor, dir string, modules []buildListModule) (map[string]string, error) {
+	latest := make(map[string]string)
+	// candidates maps the module path of a newer major version to the module path of the module in the build list
+	candidates := make(map[string]string)
+	for _, mod := range modules {
+		if next := nextMajorPath(mod.Path, mod.Version); next != "" {
+			candidates[next] = mod.Path
+		}
+	}
+	for len(candidates) > 0 {
+		var queries []string
+		for candidate := range candidates {
+			queries = append(queries, candidate+"@latest")
+		}
+		sort.Strings(queries)
+		args := append([]string{"-mod=readonly", "-e"}, queries...)
+		results, err := listModules(ctx, executor, dir, args...)
+		if err != nil {
+			return nil, err
+		}
+		nextCandidates := make(map[string]string)
+		for _, result := range results {
+			modPath, ok := candidates[result.Path]
+			if !ok || result.Error != nil || result.Version == "" {
+				continue
+			}
+			latest[modPath] = moduleVersionString(result.Path, result.Version)
+			if next := nextMajorPath(result.Path, result.Version); next != "" {
+				nextCandidates[next] = modPath
+			}
+		}
+		candidates = nextCandidates
+	}
+	return latest, nil
+}
+
+// nextMajorPath returns the module path of the major version that follows the provided version of the module (for
+// example, "example.com/mod/v2" for "example.com/mod" at v1.2.3). Major versions 0 and 1 share the module path without
+// a major version suffix, so the path of the major version that follows both is "example.com/mod/v2". Returns the empty
+// string if the path cannot be split into a prefix and a major version suffix.
+func nextMajorPath(modPath, version string) string {
+	prefix, pathMajor, ok := modmodule.SplitPathVersion(modPath)
+	if !ok {
+		return ""
+	}
+	var major int
+	if _, err := fmt.Sscanf(semver.Major(version), "v%d", &major); err != nil {
+		return ""
+	}
+	// v0 and v1 are followed by v2
+	major = max(major, 1)
+	if strings.HasPrefix(pathMajor, ".") {
+		// gopkg.in paths use a ".vN" suffix for every major version
+		return fmt.Sprintf("%s.v%d", prefix, major+1)
+	}
+	return fmt.Sprintf("%s/v%d", prefix, major+1)
+}
+
+// WriteJSON writes the report to w as indented JSON.
+func (r *OutdatedReport) WriteJSON(w io.Writer) error {
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal report as JSON")
+	}
+	if _, err := fmt.Fprintln(w, string(out)); err != nil {
+		return errors.Wrapf(err, "failed to write report")
+	}
+	return nil
+}
+
+// WriteTable writes the report to w as a table for every module.
+func (r *OutdatedReport) WriteTable(w io.Writer) error {
+	for i, mod := range r.Modules {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		if len(mod.Requirements) == 0 {
+			_, _ = fmt.Fprintf(w, "%s: all requirements are up-to-date\n", mod.Path)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s:\n", mod.Path)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "MODULE\tVERSION\tLATEST COMPATIBLE\tLATEST MAJOR\tDIRECT\tUPDATE")
+		for _, req := range mod.Requirements {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\t%s\n", req.Path, req.Version, orDash(req.LatestCompatible), orDash(req.LatestMajor), req.Direct, orDash(string(req.Update)))
+		}
+		if err := tw.Flush(); err != nil {
+			return errors.Wrapf(err, "failed to write report")
+		}
+	}
+	return nil
+}
+
+// orDash returns s or "-" if s is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextMajorPath(t *testing.T) {
+	for i, tc := range []struct {
+		path    string
+		version string
+		want    string
+	}{
+		{"github.com/pkg/errors", "v0.9.1", "github.com/pkg/errors/v2"},
+		{"github.com/pkg/errors", "v1.2.3", "github.com/pkg/errors/v2"},
+		{"gopkg.in/check.v1", "v0.0.0-20201130134442-10cb98267c6c", "gopkg.in/check.v2"},
+		{"github.com/foo/bar/v2", "v2.1.0", "github.com/foo/bar/v3"},
+		{"github.com/docker/docker", "v20.10.0+incompatible", "github.com/docker/docker/v21"},
+		{"gopkg.in/yaml.v2", "v2.4.0", "gopkg.in/yaml.v3"},
+	} {
+		assert.Equal(t, tc.want, nextMajorPath(tc.path, tc.version), "Case %d: %s@%s", i, tc.path, tc.version)
+	}
+}
+
+func TestOutdatedRequirements(t *testing.T) {
+	buildList := []buildListModule{
+		{Path: "github.com/pkg/errors", Version: "v0.9.1", Update: &buildListModule{Path: "github.com/pkg/errors", Version: "v0.9.3"}},
+		{Path: "github.com/foo/bar", Version: "v1.2.0", Indirect: true, Update: &buildListModule{Path: "github.com/foo/bar", Version: "v1.4.0"}},
+		{Path: "github.com/foo/baz", Version: "v1.0.0"},
+		{Path: "github.com/foo/qux", Version: "v1.0.0"},
+		{Path: "github.com/foo/zero", Version: "v0.9.1", Update: &buildListModule{Path: "github.com/foo/zero", Version: "v0.10.0"}},
+	}
+	assert.Equal(t, []OutdatedRequirement{
+		{Path: "github.com/pkg/errors", Version: "v0.9.1", Direct: true, Update: UpdatePatch, LatestCompatible: "v0.9.3", LatestMajor: "github.com/pkg/errors/v3@v3.1.0"},
+		{Path: "github.com/foo/bar", Version: "v1.2.0", Update: UpdateMinor, LatestCompatible: "v1.4.0"},
+		{Path: "github.com/foo/qux", Version: "v1.0.0", Direct: true, LatestMajor: "github.com/foo/qux/v2@v2.0.1"},
+		{Path: "github.com/foo/zero", Version: "v0.9.1", Direct: true, Update: UpdateMinor, LatestCompatible: "v0.10.0"},
+	}, outdatedRequirements(buildList, map[string]string{
+		"github.com/pkg/errors": "github.com/pkg/errors/v3@v3.1.0",
+		"github.com/foo/qux":    "github.com/foo/qux/v2@v2.0.1",
+	}))
+}
+
+func TestOutdatedReportWriteTable(t *testing.T) {
+	report := &OutdatedReport{
+		Modules: []OutdatedModuleReport{
+			{
+				Path: "github.com/mod/test",
+				Dir:  ".",
+				Requirements: []OutdatedRequirement{
+					{Path: "github.com/pkg/errors", Version: "v0.9.1", Direct: true, Update: UpdatePatch, LatestCompatible: "v0.9.3", LatestMajor: "github.com/pkg/errors/v2@v2.0.0"},
+					{Path: "github.com/foo/bar", Version: "v1.2.0", Update: UpdateMinor, LatestCompatible: "v1.4.0"},
+					{Path: "github.com/foo/qux", Version: "v1.0.0", Direct: true, LatestMajor: "github.com/foo/qux/v2@v2.0.1"},
+				},
+			},
+			{
+				Path: "github.com/mod/other",
+				Dir:  "other",
+			},
+		},
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.WriteTable(buf))
+	assert.Equal(t, `github.com/mod/test:
+MODULE                 VERSION  LATEST COMPATIBLE  LATEST MAJOR                     DIRECT  UPDATE
+github.com/pkg/errors  v0.9.1   v0.9.3             github.com/pkg/errors/v2@v2.0.0  true    patch
+github.com/foo/bar     v1.2.0   v1.4.0             -                                false   minor
+github.com/foo/qux     v1.0.0   -                  github.com/foo/qux/v2@v2.0.1     true    -
+
+github.com/mod/other: all requirements are up-to-date
+`, buf.String())
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// forEachParallel calls fn for every index in [0, n) using at most concurrency goroutines and returns once all of the
+// calls have completed. If concurrency is less than 1, the value of runtime.GOMAXPROCS is used.
+func forEachParallel(n, concurrency int, fn func(i int)) {
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// writePrefixed writes every line of output to w prefixed with "<prefix>: ".
+func writePrefixed(w io.Writer, prefix string, output []byte) {
+	for _, line := range bytes.SplitAfter(output, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s: %s", prefix, line)
+		if !bytes.HasSuffix(line, []byte("\n")) {
+			_, _ = fmt.Fprintln(w)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachParallelBoundsConcurrency(t *testing.T) {
+	var running, maxRunning int32
+	var calls [10]int32
+	forEachParallel(len(calls), 3, func(i int) {
+		curr := atomic.AddInt32(&running, 1)
+		for {
+			prevMax := atomic.LoadInt32(&maxRunning)
+			if curr <= prevMax || atomic.CompareAndSwapInt32(&maxRunning, prevMax, curr) {
+				break
+			}
+		}
+		atomic.AddInt32(&calls[i], 1)
+		atomic.AddInt32(&running, -1)
+	})
+	assert.LessOrEqual(t, maxRunning, int32(3))
+	for i, numCalls := range calls {
+		assert.Equal(t, int32(1), numCalls, "index %d", i)
+	}
+}
+
+func TestWritePrefixed(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writePrefixed(buf, "github.com/mod/test", []byte("go: finding module\ngo: found module"))
+	assert.Equal(t, "github.com/mod/test: go: finding module\ngithub.com/mod/test: go: found module\n", buf.String())
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"github.com/palantir/pkg/matcher"
+	"github.com/pkg/errors"
+)
+
+// VendorMode specifies how the task determines whether "go mod vendor" is run after "go mod tidy".
+type VendorMode string
+
+const (
+	// VendorModeAuto determines whether to vendor based on the environment of the project.
+	VendorModeAuto VendorMode = "auto"
+	// VendorModeAlways always runs "go mod vendor".
+	VendorModeAlways VendorMode = "always"
+	// VendorModeNever never runs "go mod vendor".
+	VendorModeNever VendorMode = "never"
+)
+
+// ParseVendorMode returns the VendorMode represented by the provided string. The empty string is parsed as
+// VendorModeAuto.
+func ParseVendorMode(in string) (VendorMode, error) {
+	switch mode := VendorMode(in); mode {
+	case "":
+		return VendorModeAuto, nil
+	case VendorModeAuto, VendorModeAlways, VendorModeNever:
+		return mode, nil
+	default:
+		return "", errors.Errorf("%q is not a valid vendor mode: must be one of %q, %q or %q", in, VendorModeAuto, VendorModeAlways, VendorModeNever)
+	}
+}
+
+// Params specifies the parameters used by Run.
+type Params struct {
+	// VendorMode specifies whether "go mod vendor" is run after "go mod tidy".
+	VendorMode VendorMode
+	// Tidy specifies the options used when running "go mod tidy".
+	Tidy TidyParams
+	// Verify specifies which checks are performed in verify mode.
+	Verify VerifyParams
+	// Workspace specifies the policy for go.work files.
+	Workspace WorkspaceParams
+	// Toolchain specifies the Go toolchain used to run the go commands.
+	Toolchain ToolchainParams
+	// Policy specifies the policies that are enforced for the dependencies of every module in verify mode.
+	Policy PolicyParams
+	// Upgrade specifies the behavior of Upgrade.
+	Upgrade UpgradeParams
+	// Concurrency is the maximum number of modules that are processed at the same time. If it is less than 1, the value
+	// of runtime.GOMAXPROCS is used.
+	Concurrency int
+	// Exclude matches the paths (relative to the project directory) that are not searched for modules.
+	Exclude matcher.Matcher
+	// Debug specifies that verification failures should include additional detail, such as the full list of files
+	// that differ in a vendor directory.
+	Debug bool
+	// Executor runs the go commands. If it is nil, a GoExecutor that runs Toolchain.GoBinary is used.
+	Executor Executor
+}
+
+// executor returns the Executor specified by the parameters or a GoExecutor for the configured go binary if none is
+// specified.
+func (p Params) executor() Executor {
+	if p.Executor == nil {
+		return GoExecutor{GoBinary: p.Toolchain.GoBinary}
+	}
+	return p.Executor
+}
+
+type TidyParams struct {
+	// Compat is provided as the value of the "-compat" flag if it is non-empty.
+	Compat string
+	// IgnoreErrors specifies whether the "-e" flag is provided.
+	IgnoreErrors bool
+}
+
+// args returns the arguments for "go mod tidy" (excluding "go mod tidy" itself) specified by the parameters.
+func (p TidyParams) args() []string {
+	var args []string
+	if p.IgnoreErrors {
+		args = append(args, "-e")
+	}
+	if p.Compat != "" {
+		args = append(args, "-compat="+p.Compat)
+	}
+	return args
+}
+
+type VerifyParams struct {
+	// SkipGoSum specifies that verification should not fail if go.sum would be modified.
+	SkipGoSum bool
+	// SkipVendor specifies that verification should not fail if the vendor directory would be modified.
+	SkipVendor bool
+}
+
+type WorkspaceParams struct {
+	// ForbidGoWork specifies that verification should fail if a go.work file is committed to the repository.
+	ForbidGoWork bool
+}
+
+type ToolchainParams struct {
+	// GoBinary is the path to the go binary used to run the go commands. If it is empty, "go" is resolved using PATH.
+	GoBinary string
+	// GoToolchain is the value of the GOTOOLCHAIN environment variable for the go commands. If it names a specific
+	// toolchain (such as "go1.22.5"), verification fails if the running toolchain is not exactly that toolchain.
+	// Verification always runs the go commands with GOTOOLCHAIN=local.
+	GoToolchain string
+	// Directive is the value of the toolchain directive written to the go.mod file of every module (such as
+	// "go1.22.5"). The directive is removed if it is redundant with the go directive. If it is empty, the toolchain
+	// directive is not modified.
+	Directive string
+}
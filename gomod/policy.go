@@ -0,0 +1,225 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+)
+
+// PolicyParams specifies the policies that are enforced for the dependencies of every module in verify mode.
+type PolicyParams struct {
+	// Modules specifies the modules that may be part of the build list.
+	Modules ModulePolicyParams
+	// Replace specifies the replace directives that are allowed in go.mod.
+	Replace ReplacePolicyParams
+	// Stability specifies the rules for unstable versions (such as pseudo-versions) in the build list.
+	Stability []StabilityRule
+	// GoVersion specifies the range of values allowed for the go directive in go.mod.
+	GoVersion GoVersionPolicyParams
+}
+
+// checkPolicies checks the go.mod file and the build list of the module against the provided policies and records the
+// results in report. goModPath is the path to the tidied go.mod file of the module, goModBefore is the content of the
+// go.mod file of the module and goModAfter is the content of the tidied go.mod file. The build list is only listed if
+// a policy requires it.
+func checkPolicies(ctx context.Context, executor Executor, mod module, goModPath string, goModBefore, goModAfter []byte, policy PolicyParams, stdout io.Writer, report *ModuleReport) error {
+	var buildList []buildListModule
+	listed := false
+	getBuildList := func() ([]buildListModule, error) {
+		if listed {
+			return buildList, nil
+		}
+		var err error
+		buildList, err = listBuildList(ctx, executor, mod.Dir, goModPath)
+		listed = err == nil
+		return buildList, err
+	}
+
+	if policy.GoVersion.enabled() {
+		if err := addGoVersionPolicyCheck(mod, goModBefore, goModAfter, policy.GoVersion, getBuildList, report); err != nil {
+			return err
+		}
+	}
+	if policy.Modules.enabled() {
+		modules, err := getBuildList()
+		if err != nil {
+			return err
+		}
+		if err := addModulePolicyCheck(ctx, executor, mod, goModPath, goModAfter, modules, policy.Modules, report); err != nil {
+			return err
+		}
+	}
+	if len(policy.Stability) > 0 {
+		modules, err := getBuildList()
+		if err != nil {
+			return err
+		}
+		if err := addStabilityPolicyCheck(mod, goModAfter, modules, policy.Stability, time.Now(), stdout, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ModulePolicyParams specifies the modules that may be part of the build list of a module. A module that matches a
+// deny rule is a violation. If any allow rules are specified, a module that does not match any of them is a violation
+// as well.
+type ModulePolicyParams struct {
+	// Deny are the rules for the modules that may not be part of the build list.
+	Deny []ModuleRule
+	// Allow are the rules for the modules that may be part of the build list. If empty, all modules that do not match a
+	// deny rule are allowed.
+	Allow []ModuleRule
+}
+
+// enabled returns true if the policy contains any rules.
+func (p ModulePolicyParams) enabled() bool {
+	return len(p.Deny) > 0 || len(p.Allow) > 0
+}
+
+// ModuleRule matches modules by path and (optionally) version.
+type ModuleRule struct {
+	// Module is the pattern that matches the module path (see MatchModulePattern).
+	Module string
+	// Versions is the range of versions matched by the rule. If empty, all versions are matched.
+	Versions VersionRange
+	// Reason describes the reason for the rule, which is included in violations.
+	Reason string
+}
+
+// matches returns true if the rule matches the module with the provided path and version.
+func (r ModuleRule) matches(modPath, version string) bool {
+	return MatchModulePattern(r.Module, modPath) && r.Versions.Contains(version)
+}
+
+// String returns the pattern and version range of the rule.
+func (r ModuleRule) String() string {
+	if len(r.Versions) == 0 {
+		return r.Module
+	}
+	return fmt.Sprintf("%s %s", r.Module, r.Versions)
+}
+
+// ValidateModulePattern returns an error if the provided module pattern is not valid.
+func ValidateModulePattern(pattern string) error {
+	if pattern == "" {
+		return errors.Errorf("module pattern must not be empty")
+	}
+	if _, err := path.Match(strings.TrimSuffix(pattern, "/..."), ""); err != nil {
+		return errors.Errorf("%q is not a valid module pattern", pattern)
+	}
+	return nil
+}
+
+// MatchModulePattern returns true if the provided module path matches the pattern. Patterns use the syntax of
+// path.Match, so "*" matches any sequence of characters other than "/". A pattern that ends in "/..." also matches
+// every module path that is nested under a path matched by the rest of the pattern: for example,
+// "github.com/palantir/..." matches "github.com/palantir" and "github.com/palantir/pkg/matcher".
+func MatchModulePattern(pattern, modPath string) bool {
+	prefix, recursive := strings.CutSuffix(pattern, "/...")
+	if !recursive {
+		ok, _ := path.Match(pattern, modPath)
+		return ok
+	}
+	for p := modPath; ; p = path.Dir(p) {
+		if ok, _ := path.Match(prefix, p); ok {
+			return true
+		}
+		if !strings.Contains(p, "/") {
+			return false
+		}
+	}
+}
+
+// VersionRange is a set of constraints on a module version, all of which must be satisfied for a version to be in the
+// range. An empty range contains every version.
+type VersionRange []VersionConstraint
+
+// VersionConstraint compares a module version with a semantic version using an operator.
+type VersionConstraint struct {
+	// Op is one of "<", "<=", ">", ">=", "=" or "!=".
+	Op string
+	// Version is a valid semantic version with a "v" prefix (such as "v1.2.0").
+	Version string
+}
+
+var versionConstraintOps = []string{"<=", ">=", "!=", "<", ">", "="}
+
+// ParseVersionRange parses a version range, which consists of constraints separated by spaces or commas. Each
+// constraint is an operator followed by a semantic version: for example, ">=v1.2.0, <v2.0.0". The empty string is
+// parsed as the range that contains every version.
+func ParseVersionRange(in string) (VersionRange, error) {
+	fields := strings.FieldsFunc(in, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	var versionRange VersionRange
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		var constraint VersionConstraint
+		for _, op := range versionConstraintOps {
+			if strings.HasPrefix(field, op) {
+				constraint.Op = op
+				break
+			}
+		}
+		if constraint.Op == "" {
+			return nil, errors.Errorf("%q is not a valid version range: constraint %q must start with one of %s", in, field, strings.Join(versionConstraintOps, ", "))
+		}
+		constraint.Version = strings.TrimPrefix(field, constraint.Op)
+		if constraint.Version == "" && i+1 < len(fields) {
+			// operator is separated from the version by a space
+			i++
+			constraint.Version = fields[i]
+		}
+		if !semver.IsValid(constraint.Version) {
+			return nil, errors.Errorf("%q is not a valid version range: %q is not a valid semantic version", in, constraint.Version)
+		}
+		versionRange = append(versionRange, constraint)
+	}
+	return versionRange, nil
+}
+
+// Contains returns true if the provided version satisfies every constraint of the range.
+func (r VersionRange) Contains(version string) bool {
+	for _, constraint := range r {
+		cmp := semver.Compare(version, constraint.Version)
+		var ok bool
+		switch constraint.Op {
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the constraints of the range separated by commas.
+func (r VersionRange) String() string {
+	var constraints []string
+	for _, constraint := range r {
+		constraints = append(constraints, constraint.Op+constraint.Version)
+	}
+	return strings.Join(constraints, ", ")
+}
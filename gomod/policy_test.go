@@ -0,0 +1,109 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchModulePattern(t *testing.T) {
+	for i, tc := range []struct {
+		pattern string
+		modPath string
+		want    bool
+	}{
+		{"github.com/pkg/errors", "github.com/pkg/errors", true},
+		{"github.com/pkg/errors", "github.com/pkg/errors/v2", false},
+		{"github.com/pkg/*", "github.com/pkg/errors", true},
+		{"github.com/pkg/*", "github.com/pkg/errors/v2", false},
+		{"github.com/palantir/...", "github.com/palantir", true},
+		{"github.com/palantir/...", "github.com/palantir/pkg/matcher", true},
+		{"github.com/palantir/...", "github.com/palantirx/pkg", false},
+		{"github.com/*/errors/...", "github.com/pkg/errors/v2", true},
+	} {
+		assert.Equal(t, tc.want, MatchModulePattern(tc.pattern, tc.modPath), "Case %d: %s %s", i, tc.pattern, tc.modPath)
+	}
+}
+
+func TestVersionRange(t *testing.T) {
+	for i, tc := range []struct {
+		versions string
+		version  string
+		want     bool
+	}{
+		{"", "v1.0.0", true},
+		{"<v1.2.0", "v1.1.9", true},
+		{"<v1.2.0", "v1.2.0", false},
+		{">= v1.2.0, < v2.0.0", "v1.5.0", true},
+		{">= v1.2.0, < v2.0.0", "v2.0.0", false},
+		{"!=v1.0.0", "v1.0.0", false},
+		{"=v1.0.0", "v1.0.0", true},
+		{"<v1.0.0", "v0.0.0-20200101000000-abcdefabcdef", true},
+	} {
+		versionRange, err := ParseVersionRange(tc.versions)
+		require.NoError(t, err, "Case %d", i)
+		assert.Equal(t, tc.want, versionRange.Contains(tc.version), "Case %d: %s %s", i, tc.versions, tc.version)
+	}
+}
+
+func TestParseVersionRangeErrors(t *testing.T) {
+	for i, tc := range []struct {
+		versions string
+		wantErr  string
+	}{
+		{"v1.0.0", `"v1.0.0" is not a valid version range: constraint "v1.0.0" must start with one of <=, >=, !=, <, >, =`},
+		{"<1.0.0", `"<1.0.0" is not a valid version range: "1.0.0" is not a valid semantic version`},
+		{">=", `">=" is not a valid version range: "" is not a valid semantic version`},
+	} {
+		_, err := ParseVersionRange(tc.versions)
+		assert.EqualError(t, err, tc.wantErr, "Case %d", i)
+	}
+}
+
+func TestModulePolicyViolations(t *testing.T) {
+	buildList := []buildListModule{
+		{Path: "github.com/mod/test", Main: true},
+		{Path: "github.com/palantir/pkg/matcher", Version: "v1.3.0"},
+		{Path: "github.com/sirupsen/logrus", Version: "v1.9.3"},
+		{Path: "github.com/pkg/errors", Version: "v0.9.1", Replace: &buildListModule{Path: "github.com/bad/errors", Version: "v0.9.2"}},
+		{Path: "golang.org/x/mod", Version: "v0.40.0"},
+	}
+	policy := ModulePolicyParams{
+		Deny: []ModuleRule{
+			{Module: "github.com/sirupsen/logrus", Reason: "use log/slog"},
+			{Module: "github.com/bad/..."},
+		},
+		Allow: []ModuleRule{
+			{Module: "github.com/..."},
+		},
+	}
+	var got []string
+	for _, violation := range modulePolicyViolations(policy, buildList) {
+		got = append(got, violation.Reason)
+	}
+	assert.Equal(t, []string{
+		`github.com/sirupsen/logrus@v1.9.3 is denied by the rule "github.com/sirupsen/logrus": use log/slog`,
+		`github.com/bad/errors@v0.9.2 is denied by the rule "github.com/bad/..."`,
+		`golang.org/x/mod@v0.40.0 does not match any allow rule`,
+	}, got)
+}
+
+func TestParseModWhy(t *testing.T) {
+	got := parseModWhy([]byte(`# github.com/pkg/errors
+github.com/mod/test
+github.com/mod/test/internal
+github.com/pkg/errors
+
+# golang.org/x/text
+(main module does not need module golang.org/x/text)
+`))
+	assert.Equal(t, map[string][]string{
+		"github.com/pkg/errors": {"github.com/mod/test", "github.com/mod/test/internal", "github.com/pkg/errors"},
+		"golang.org/x/text":     nil,
+	}, got)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+)
+
+// ReplacePolicyParams specifies the replace directives that are allowed in the go.mod file of every module.
+type ReplacePolicyParams struct {
+	// ForbidLocalPaths specifies that replace directives that point to a directory are forbidden unless the directory
+	// matches one of AllowedLocalPaths.
+	ForbidLocalPaths bool
+	// AllowedLocalPaths are the patterns (in the syntax of path.Match) for the directories that replace directives may
+	// point to if ForbidLocalPaths is true. The patterns are matched against the cleaned path as written in go.mod,
+	// which is relative to the module directory. A directory outside of the project is never allowed.
+	AllowedLocalPaths []string
+	// AllowedForks are the module patterns (see MatchModulePattern) for the modules that replace directives may point
+	// to. If empty, replace directives may point to any module. A replace directive that only changes the version of a
+	// module is always allowed.
+	AllowedForks []string
+}
+
+// enabled returns true if the policy restricts any replace directives.
+func (p ReplacePolicyParams) enabled() bool {
+	return p.ForbidLocalPaths || len(p.AllowedForks) > 0
+}
+
+// ValidateLocalPathPattern returns an error if the provided pattern for a local replacement directory is not valid.
+func ValidateLocalPathPattern(pattern string) error {
+	if pattern == "" {
+		return errors.Errorf("path pattern must not be empty")
+	}
+	if path.IsAbs(pattern) || filepath.IsAbs(pattern) {
+		return errors.Errorf("%q is not a valid path pattern: must be relative to the module directory", pattern)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return errors.Errorf("%q is not a valid path pattern", pattern)
+	}
+	return nil
+}
+
+// replacePolicyViolations returns the violations of the policy by the replace directives of the provided go.mod file
+// of the module. Each violation starts with the location of the directive in the go.mod file.
+func replacePolicyViolations(policy ReplacePolicyParams, mod module, modFile *modfile.File) []string {
+	var violations []string
+	for _, rep := range modFile.Replace {
+		var reason string
+		if modfile.IsDirectoryPath(rep.New.Path) {
+			reason = localReplaceViolation(policy, mod, rep.New.Path)
+		} else if len(policy.AllowedForks) > 0 && rep.New.Path != rep.Old.Path && !matchesAnyModulePattern(policy.AllowedForks, rep.New.Path) {
+			reason = fmt.Sprintf("%s does not match any of the allowed forks (%s)", rep.New.Path, strings.Join(policy.AllowedForks, ", "))
+		}
+		if reason == "" {
+			continue
+		}
+		violations = append(violations, fmt.Sprintf("%s:%d: replace %s => %s: %s",
+			mod.relPath("go.mod"), rep.Syntax.Start.Line,
+			strings.TrimSpace(rep.Old.Path+" "+rep.Old.Version), strings.TrimSpace(rep.New.Path+" "+rep.New.Version), reason))
+	}
+	return violations
+}
+
+// localReplaceViolation returns the reason why a replace directive that points to the provided directory violates the
+// policy or the empty string if it is allowed.
+func localReplaceViolation(policy ReplacePolicyParams, mod module, dir string) string {
+	if !policy.ForbidLocalPaths {
+		return ""
+	}
+	if path.IsAbs(dir) || filepath.IsAbs(dir) {
+		return "replacement is an absolute path"
+	}
+	cleanDir := path.Clean(filepath.ToSlash(dir))
+	if projectRelDir := path.Join(filepath.ToSlash(mod.RelDir), cleanDir); projectRelDir == ".." || strings.HasPrefix(projectRelDir, "../") {
+		return "replacement is a directory outside of the project"
+	}
+	for _, pattern := range policy.AllowedLocalPaths {
+		if ok, _ := path.Match(path.Clean(pattern), cleanDir); ok {
+			return ""
+		}
+	}
+	return "replacement is a local directory that is not in the allowed local paths"
+}
+
+// matchesAnyModulePattern returns true if the provided module path matches any of the patterns.
+func matchesAnyModulePattern(patterns []string, modPath string) bool {
+	for _, pattern := range patterns {
+		if MatchModulePattern(pattern, modPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// addReplacePolicyCheck records the result of checking the replace directives in the provided content of the go.mod
+// file of the module against the policy in report.
+func addReplacePolicyCheck(mod module, goMod []byte, policy ReplacePolicyParams, report *ModuleReport) error {
+	goModPath := filepath.Join(mod.Dir, "go.mod")
+	modFile, err := modfile.Parse(goModPath, goMod, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse %s", goModPath)
+	}
+	var failure string
+	if violations := replacePolicyViolations(policy, mod, modFile); len(violations) > 0 {
+		failure = fmt.Sprintf("%s violates the replace policy:\n%s", mod.relPath("go.mod"), strings.Join(violations, "\n"))
+	}
+	report.addCheck(checkReplacePolicy, failure)
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/modfile"
+)
+
+func TestReplacePolicyViolations(t *testing.T) {
+	const goMod = `module github.com/mod/test
+
+go 1.22
+
+require github.com/pkg/errors v0.9.1
+
+replace (
+	github.com/pkg/errors => ../errors
+	github.com/mod/shared => ../shared
+	github.com/mod/tools v1.0.0 => ./tools
+)
+
+replace github.com/other/mod => /home/dev/mod
+
+replace github.com/forked/mod v1.2.0 => github.com/someone/mod v1.2.1
+
+replace github.com/approved/mod => github.com/palantir/mod-fork v1.0.0
+
+replace github.com/pinned/mod => github.com/pinned/mod v1.0.1
+`
+	for i, tc := range []struct {
+		name   string
+		relDir string
+		policy ReplacePolicyParams
+		want   []string
+	}{
+		{
+			name:   "local paths forbidden",
+			relDir: "sub",
+			policy: ReplacePolicyParams{ForbidLocalPaths: true, AllowedLocalPaths: []string{"./tools", "../shared"}},
+			want: []string{
+				"sub/go.mod:8: replace github.com/pkg/errors => ../errors: replacement is a local directory that is not in the allowed local paths",
+				"sub/go.mod:13: replace github.com/other/mod => /home/dev/mod: replacement is an absolute path",
+			},
+		},
+		{
+			name:   "allowed local path outside of the project",
+			relDir: ".",
+			policy: ReplacePolicyParams{ForbidLocalPaths: true, AllowedLocalPaths: []string{"../*", "tools"}},
+			want: []string{
+				"go.mod:8: replace github.com/pkg/errors => ../errors: replacement is a directory outside of the project",
+				"go.mod:9: replace github.com/mod/shared => ../shared: replacement is a directory outside of the project",
+				"go.mod:13: replace github.com/other/mod => /home/dev/mod: replacement is an absolute path",
+			},
+		},
+		{
+			name:   "allowed forks",
+			relDir: ".",
+			policy: ReplacePolicyParams{AllowedForks: []string{"github.com/palantir/..."}},
+			want: []string{
+				"go.mod:15: replace github.com/forked/mod v1.2.0 => github.com/someone/mod v1.2.1: github.com/someone/mod does not match any of the allowed forks (github.com/palantir/...)",
+			},
+		},
+	} {
+		modFile, err := modfile.Parse("go.mod", []byte(goMod), nil)
+		require.NoError(t, err, "Case %d: %s", i, tc.name)
+		got := replacePolicyViolations(tc.policy, module{RelDir: tc.relDir}, modFile)
+		assert.Equal(t, tc.want, got, "Case %d: %s", i, tc.name)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Report is the machine-readable result of running the task.
+type Report struct {
+	// ProjectDir is the absolute path to the project directory.
+	ProjectDir string `json:"projectDir"`
+	// Verify is true if the task was run in verify mode, in which case the changes are the ones that would be made.
+	Verify bool `json:"verify"`
+	// DurationSeconds is the total duration of the task.
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Modules are the results for the modules in the project in the order in which they were processed.
+	Modules []ModuleReport `json:"modules"`
+	// Workspace is the result for the go.work file of the project, if any. Its path is "go.work".
+	Workspace *ModuleReport `json:"workspace,omitempty"`
+	// Checks are the results of the verification checks that apply to the whole project rather than to a single
+	// module.
+	Checks []CheckResult `json:"checks,omitempty"`
+	// Failures are the verification failures that apply to the whole project rather than to a single module.
+	Failures []string `json:"failures,omitempty"`
+	// Error is the error that caused the task to fail, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// ModuleReport is the result of running the task for a single module (or for the go.work file of the project).
+type ModuleReport struct {
+	// Path is the module path.
+	Path string `json:"path"`
+	// Dir is the directory of the module relative to the project directory.
+	Dir string `json:"dir"`
+	// Vendor is the decision of whether vendoring is enabled.
+	Vendor VendorDecision `json:"vendor"`
+	// GoVersion is the go directive of the module after running "go mod tidy". It is empty for the go.work file of the
+	// project and for modules without a go directive.
+	GoVersion string `json:"goVersion,omitempty"`
+	// Steps are the go commands that were run in the order in which they were run.
+	Steps []StepReport `json:"steps"`
+	// Changes are the files that were modified (or, in verify mode, would be modified).
+	Changes []FileChange `json:"changes,omitempty"`
+	// Checks are the results of the verification checks for the module.
+	Checks []CheckResult `json:"checks,omitempty"`
+	// Failures are the verification failures for the module.
+	Failures []string `json:"failures,omitempty"`
+	// Warnings are the findings of the verification checks for the module that do not cause verification to fail.
+	Warnings []string `json:"warnings,omitempty"`
+	// Output is the output of the go commands.
+	Output string `json:"output,omitempty"`
+	// DurationSeconds is the total duration of processing the module.
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Error is the error that occurred while processing the module, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// CheckStatus is the outcome of a verification check.
+type CheckStatus string
+
+const (
+	CheckPassed  CheckStatus = "passed"
+	CheckFailed  CheckStatus = "failed"
+	CheckSkipped CheckStatus = "skipped"
+)
+
+// Names of the verification checks.
+const (
+	checkGoMod              = "go.mod"
+	checkGoSum              = "go.sum"
+	checkVendor             = "vendor"
+	checkModulesTxt         = "modules.txt"
+	checkVendorHashes       = "vendor hashes"
+	checkModulePolicy       = "module policy"
+	checkReplacePolicy      = "replace policy"
+	checkStabilityPolicy    = "version stability"
+	checkGoVersionPolicy    = "go version"
+	checkGoVersionAlignment = "go version alignment"
+	checkToolchain          = "toolchain"
+	checkToolchainDirective = "toolchain directive"
+	checkGoWorkSync         = "go work sync"
+	checkForbidGoWork       = "forbid-go-work"
+)
+
+// CheckResult is the result of a verification check.
+type CheckResult struct {
+	// Name is the name of the check (for example, "go.mod" or "vendor").
+	Name string `json:"name"`
+	// Status is the outcome of the check.
+	Status CheckStatus `json:"status"`
+	// Message describes the failure (including any diffs) if the check failed or the reason if it was skipped.
+	Message string `json:"message,omitempty"`
+}
+
+// newCheckResult returns the result of the check with the provided name. If failure is empty, the check passed.
+// Otherwise, it failed with failure as its message.
+func newCheckResult(name, failure string) CheckResult {
+	if failure == "" {
+		return CheckResult{Name: name, Status: CheckPassed}
+	}
+	return CheckResult{Name: name, Status: CheckFailed, Message: failure}
+}
+
+// addCheck records the result of the check with the provided name. If failure is non-empty, the check failed and the
+// failure is also recorded in Failures.
+func (r *ModuleReport) addCheck(name, failure string) {
+	r.Checks = append(r.Checks, newCheckResult(name, failure))
+	if failure != "" {
+		r.Failures = append(r.Failures, failure)
+	}
+}
+
+// skipCheck records that the check with the provided name was skipped for the provided reason.
+func (r *ModuleReport) skipCheck(name, reason string) {
+	r.Checks = append(r.Checks, CheckResult{Name: name, Status: CheckSkipped, Message: reason})
+}
+
+// StepReport records a go command that was run.
+type StepReport struct {
+	// Args are the arguments of the command, starting with "go".
+	Args []string `json:"args"`
+	// DurationSeconds is the duration of the command.
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Error is the error returned by the command, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// FileChangeType describes how a file or directory was changed.
+type FileChangeType string
+
+const (
+	FileCreated  FileChangeType = "created"
+	FileRemoved  FileChangeType = "removed"
+	FileModified FileChangeType = "modified"
+)
+
+// FileChange describes a change to a file or directory in the project.
+type FileChange struct {
+	// Path is the path to the file or directory relative to the project directory.
+	Path string `json:"path"`
+	// Type is the type of the change.
+	Type FileChangeType `json:"type"`
+	// Diff is the unified diff of the change for files.
+	Diff string `json:"diff,omitempty"`
+	// ChecksumDiffs are the entries of the dirchecksum.ChecksumsDiff of the change for directories, keyed by the path
+	// of the file relative to the directory.
+	ChecksumDiffs map[string]string `json:"checksumDiffs,omitempty"`
+	// Modules are the differences of a vendor directory grouped by the module that provides the files.
+	Modules []ModuleDrift `json:"modules,omitempty"`
+	// OtherFiles are the paths (relative to the directory) of the differing files of a vendor directory that are not
+	// provided by any module, such as modules.txt.
+	OtherFiles []string `json:"otherFiles,omitempty"`
+}
+
+// WriteJSON writes the report to w as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal report as JSON")
+	}
+	if _, err := fmt.Fprintln(w, string(out)); err != nil {
+		return errors.Wrapf(err, "failed to write report")
+	}
+	return nil
+}
+
+// errorString returns the message of err or the empty string if err is nil.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// stepRecorder is an Executor that records every command run by the wrapped Executor along with its duration.
+type stepRecorder struct {
+	Executor
+
+	mu    sync.Mutex
+	steps []StepReport
+}
+
+func (r *stepRecorder) Run(ctx context.Context, cmd Command) error {
+	start := time.Now()
+	err := r.Executor.Run(ctx, cmd)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, StepReport{
+		Args:            append([]string{"go"}, cmd.Args...),
+		DurationSeconds: time.Since(start).Seconds(),
+		Error:           errorString(err),
+	})
+	return err
+}
+
+func (r *stepRecorder) recordedSteps() []StepReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]StepReport{}, r.steps...)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithReportVerify(t *testing.T) {
+	projectDir := newTestModule(t, true)
+	toolchain := newUpToDateToolchain()
+	toolchain.vendorFiles["github.com/pkg/errors/errors.go"] = "package errors // modified\n"
+	toolchain.vendorFiles["github.com/pkg/errors/stack.go"] = "package errors\n"
+
+	report, err := RunWithReport(context.Background(), projectDir, true, Params{Executor: toolchain}, &bytes.Buffer{})
+	require.Error(t, err)
+	require.NotNil(t, report)
+
+	assert.Equal(t, projectDir, report.ProjectDir)
+	assert.True(t, report.Verify)
+	assert.Equal(t, err.Error(), report.Error)
+	require.Len(t, report.Modules, 1)
+
+	modReport := report.Modules[0]
+	assert.Equal(t, "github.com/mod/test", modReport.Path)
+	assert.Equal(t, ".", modReport.Dir)
+	assert.Equal(t, VendorDecision{Enabled: true, Reason: "vendor/modules.txt exists and the go directive in go.mod is 1.22"}, modReport.Vendor)
+	assert.Equal(t, "1.22", modReport.GoVersion)
+
+	var steps [][]string
+	for _, step := range modReport.Steps {
+		steps = append(steps, step.Args[:3])
+	}
+	assert.Equal(t, [][]string{
+		{"go", "env", "-json"},
+		{"go", "env", "-json"},
+		{"go", "mod", "tidy"},
+		{"go", "mod", "vendor"},
+	}, steps)
+
+	assert.Equal(t, []FileChange{
+		{
+			Path: "vendor",
+			Type: FileModified,
+			ChecksumDiffs: map[string]string{
+				"github.com/pkg/errors/errors.go": fmt.Sprintf("checksum changed from %x to %x", sha256.Sum256([]byte(testVendoredSrc)), sha256.Sum256([]byte("package errors // modified\n"))),
+				"github.com/pkg/errors/stack.go":  "extra",
+			},
+			Modules: []ModuleDrift{
+				{
+					Path:   "github.com/pkg/errors",
+					Type:   ModuleFilesModified,
+					Before: "v0.9.1",
+					After:  "v0.9.1",
+					Files:  []string{"github.com/pkg/errors/errors.go", "github.com/pkg/errors/stack.go"},
+				},
+			},
+		},
+	}, modReport.Changes)
+	var checks []string
+	for _, check := range modReport.Checks {
+		checks = append(checks, check.Name+" "+string(check.Status))
+	}
+	assert.Equal(t, []string{"modules.txt passed", "vendor hashes skipped", "go.mod passed", "go.sum passed", "vendor failed"}, checks)
+	require.Len(t, modReport.Failures, 1)
+	assert.Contains(t, modReport.Failures[0], "vendor directory modified:\n")
+	assert.Contains(t, modReport.Output, "vendoring enabled")
+
+	// report can be serialized as JSON
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.WriteJSON(buf))
+	var unmarshalled Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &unmarshalled))
+	assert.Equal(t, *report, unmarshalled)
+}
+
+func TestRunWithReportApply(t *testing.T) {
+	projectDir := newTestModule(t, false)
+	toolchain := newUpToDateToolchain()
+	toolchain.tidyGoMod = "module github.com/mod/test\n\ngo 1.22\n"
+	toolchain.tidyGoSum = ""
+
+	report, err := RunWithReport(context.Background(), projectDir, false, Params{Executor: toolchain}, &bytes.Buffer{})
+	require.NoError(t, err)
+	require.Len(t, report.Modules, 1)
+
+	var changes []string
+	for _, change := range report.Modules[0].Changes {
+		changes = append(changes, change.Path+" "+string(change.Type))
+	}
+	assert.Equal(t, []string{"go.mod modified", "go.sum modified"}, changes)
+	assert.Empty(t, report.Modules[0].Failures)
+	assert.Empty(t, report.Error)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// fileSnapshot records the content of a set of files so that they can be restored to that content later. A nil
+// content records that the file did not exist.
+type fileSnapshot struct {
+	paths   []string
+	content map[string][]byte
+}
+
+type fileChange struct {
+	path   string
+	before []byte
+	after  []byte
+}
+
+func newFileSnapshot(paths ...string) (*fileSnapshot, error) {
+	snapshot := &fileSnapshot{
+		paths:   paths,
+		content: make(map[string][]byte, len(paths)),
+	}
+	for _, path := range paths {
+		content, err := readFileIfExists(path)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.content[path] = content
+	}
+	return snapshot, nil
+}
+
+// restore writes the content recorded in the snapshot to the files, removing the files that did not exist when the
+// snapshot was taken.
+func (s *fileSnapshot) restore() error {
+	for _, path := range s.paths {
+		content := s.content[path]
+		if content == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "failed to remove %s", path)
+			}
+			continue
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return errors.Wrapf(err, "failed to restore %s", path)
+		}
+	}
+	return nil
+}
+
+// readFileIfExists returns the content of the file at path, or nil if the file does not exist. The content of an
+// existing empty file is a non-nil empty slice.
+func readFileIfExists(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", path)
+	}
+	if content == nil {
+		content = []byte{}
+	}
+	return content, nil
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	modmodule "golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// VersionKind is a kind of module version that may be unstable.
+type VersionKind string
+
+const (
+	// VersionKindPseudo is a pseudo-version, which refers to a commit rather than a tagged release.
+	VersionKindPseudo VersionKind = "pseudo-version"
+	// VersionKindPrerelease is a tagged pre-release version such as "v1.2.0-rc.1".
+	VersionKindPrerelease VersionKind = "prerelease"
+	// VersionKindIncompatible is a version with the "+incompatible" suffix, which is a major version of at least 2 of
+	// a module that does not use a major version suffix in its module path.
+	VersionKindIncompatible VersionKind = "incompatible"
+	// VersionKindV0 is a version with major version 0, which makes no compatibility guarantees.
+	VersionKindV0 VersionKind = "v0"
+)
+
+var versionKinds = []VersionKind{VersionKindPseudo, VersionKindPrerelease, VersionKindIncompatible, VersionKindV0}
+
+// ParseVersionKind returns the VersionKind represented by the provided string.
+func ParseVersionKind(in string) (VersionKind, error) {
+	for _, kind := range versionKinds {
+		if VersionKind(in) == kind {
+			return kind, nil
+		}
+	}
+	return "", errors.Errorf("%q is not a valid version kind: must be one of %q, %q, %q or %q", in, VersionKindPseudo, VersionKindPrerelease, VersionKindIncompatible, VersionKindV0)
+}
+
+// description returns the description of a version of the kind, such as "pre-release version".
+func (k VersionKind) description() string {
+	switch k {
+	case VersionKindPseudo:
+		return "pseudo-version"
+	case VersionKindPrerelease:
+		return "pre-release version"
+	case VersionKindIncompatible:
+		return "+incompatible version"
+	default:
+		return string(k) + " version"
+	}
+}
+
+// versionKindsOf returns the kinds of the provided version. A pseudo-version is not considered a pre-release version
+// even though it uses the pre-release syntax.
+func versionKindsOf(version string) []VersionKind {
+	var kinds []VersionKind
+	if modmodule.IsPseudoVersion(version) {
+		kinds = append(kinds, VersionKindPseudo)
+	} else if semver.Prerelease(version) != "" {
+		kinds = append(kinds, VersionKindPrerelease)
+	}
+	if semver.Build(version) == "+incompatible" {
+		kinds = append(kinds, VersionKindIncompatible)
+	}
+	if semver.Major(version) == "v0" {
+		kinds = append(kinds, VersionKindV0)
+	}
+	return kinds
+}
+
+// StabilityAction is the action taken for a module version that matches a StabilityRule.
+type StabilityAction string
+
+const (
+	// StabilityActionFail fails verification.
+	StabilityActionFail StabilityAction = "fail"
+	// StabilityActionWarn prints a warning without failing verification.
+	StabilityActionWarn StabilityAction = "warn"
+	// StabilityActionAllow allows the version, which is used to make exceptions to other rules.
+	StabilityActionAllow StabilityAction = "allow"
+)
+
+// ParseStabilityAction returns the StabilityAction represented by the provided string.
+func ParseStabilityAction(in string) (StabilityAction, error) {
+	switch action := StabilityAction(in); action {
+	case StabilityActionFail, StabilityActionWarn, StabilityActionAllow:
+		return action, nil
+	default:
+		return "", errors.Errorf("%q is not a valid action: must be one of %q, %q or %q", in, StabilityActionFail, StabilityActionWarn, StabilityActionAllow)
+	}
+}
+
+// StabilityRule specifies the action taken for the versions of a kind of the modules that match a pattern. For every
+// module in the build list and every kind of its version, the first matching rule that has not expired is applied.
+type StabilityRule struct {
+	// Kind is the kind of version matched by the rule.
+	Kind VersionKind
+	// Module is the pattern that matches the module path (see MatchModulePattern). If empty, every module is matched.
+	Module string
+	// Action is the action taken for matching versions.
+	Action StabilityAction
+	// Expires is the time after which the rule is no longer applied, which is used for temporary exceptions. If zero,
+	// the rule never expires.
+	Expires time.Time
+	// Reason describes the reason for the rule, which is included in violations.
+	Reason string
+}
+
+// String returns a description of the rule.
+func (r StabilityRule) String() string {
+	module := r.Module
+	if module == "" {
+		module = "all modules"
+	}
+	return fmt.Sprintf("%s %s for %s", r.Action, r.Kind, module)
+}
+
+// expired returns true if the rule has expired at the provided time.
+func (r StabilityRule) expired(now time.Time) bool {
+	return !r.Expires.IsZero() && now.After(r.Expires)
+}
+
+// stabilityViolations returns the failures and warnings for the versions of the modules in the provided build list
+// according to the rules. The version of a module that is replaced by another module is the version of the
+// replacement. The location of the require directive of each module in go.mod is taken from requireLines, if present.
+func stabilityViolations(rules []StabilityRule, buildList []buildListModule, requireLines map[string]string, now time.Time) (failures, warnings []string) {
+	expiredRules := make(map[int]bool)
+	for _, mod := range buildList {
+		if mod.Main {
+			continue
+		}
+		version := mod.Version
+		if mod.Replace != nil && mod.Replace.Version != "" {
+			version = mod.Replace.Version
+		}
+		for _, kind := range versionKindsOf(version) {
+			for i, rule := range rules {
+				if rule.Kind != kind || (rule.Module != "" && !MatchModulePattern(rule.Module, mod.Path)) {
+					continue
+				}
+				if rule.expired(now) {
+					if !expiredRules[i] {
+						expiredRules[i] = true
+						warnings = append(warnings, fmt.Sprintf("the rule %q expired on %s", rule.String(), rule.Expires.Format(time.DateOnly)))
+					}
+					continue
+				}
+				if rule.Action == StabilityActionAllow {
+					break
+				}
+				violation := fmt.Sprintf("%s is a %s", moduleVersionString(mod.Path, version), kind.description())
+				if rule.Reason != "" {
+					violation += ": " + rule.Reason
+				}
+				if line, ok := requireLines[mod.Path]; ok {
+					violation += fmt.Sprintf(" (%s)", line)
+				}
+				if rule.Action == StabilityActionFail {
+					failures = append(failures, violation)
+				} else {
+					warnings = append(warnings, violation)
+				}
+				break
+			}
+		}
+	}
+	return failures, warnings
+}
+
+// addStabilityPolicyCheck records the result of checking the versions of the modules in the provided build list of the
+// module against the rules in report. goMod is the content of the go.mod file of the module. The warnings are recorded
+// in report and written to stdout.
+func addStabilityPolicyCheck(mod module, goMod []byte, buildList []buildListModule, rules []StabilityRule, now time.Time, stdout io.Writer, report *ModuleReport) error {
+	lines, err := requireLines(mod.relPath("go.mod"), goMod)
+	if err != nil {
+		return err
+	}
+	failures, warnings := stabilityViolations(rules, buildList, lines, now)
+	for _, warning := range warnings {
+		_, _ = fmt.Fprintf(stdout, "warning: %s\n", warning)
+	}
+	report.Warnings = append(report.Warnings, warnings...)
+	var failure string
+	if len(failures) > 0 {
+		failure = fmt.Sprintf("%s requires unstable versions:\n%s", mod.relPath("go.mod"), strings.Join(failures, "\n"))
+	}
+	report.addCheck(checkStabilityPolicy, failure)
+	return nil
+}
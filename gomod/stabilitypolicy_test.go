@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionKindsOf(t *testing.T) {
+	for i, tc := range []struct {
+		version string
+		want    []VersionKind
+	}{
+		{"v1.2.3", nil},
+		{"v0.9.1", []VersionKind{VersionKindV0}},
+		{"v1.3.0-rc.1", []VersionKind{VersionKindPrerelease}},
+		{"v0.0.0-20240102030405-abcdefabcdef", []VersionKind{VersionKindPseudo, VersionKindV0}},
+		{"v1.2.4-0.20240102030405-abcdefabcdef", []VersionKind{VersionKindPseudo}},
+		{"v2.0.0+incompatible", []VersionKind{VersionKindIncompatible}},
+	} {
+		assert.Equal(t, tc.want, versionKindsOf(tc.version), "Case %d: %s", i, tc.version)
+	}
+}
+
+func TestStabilityViolations(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	buildList := []buildListModule{
+		{Path: "github.com/mod/test", Main: true},
+		{Path: "github.com/pkg/errors", Version: "v0.9.1"},
+		{Path: "github.com/palantir/pkg", Version: "v0.0.0-20240102030405-abcdefabcdef"},
+		{Path: "github.com/other/pkg", Version: "v0.0.0-20240102030405-abcdefabcdef"},
+		{Path: "github.com/old/pkg", Version: "v1.2.4-0.20240102030405-abcdefabcdef"},
+		{Path: "github.com/docker/docker", Version: "v20.10.0+incompatible"},
+		{Path: "github.com/forked/pkg", Version: "v1.0.0", Replace: &buildListModule{Path: "github.com/fork/pkg", Version: "v1.1.0-beta.1"}},
+	}
+	rules := []StabilityRule{
+		{Kind: VersionKindPseudo, Module: "github.com/palantir/...", Action: StabilityActionAllow, Expires: now.Add(time.Hour)},
+		{Kind: VersionKindPseudo, Module: "github.com/old/pkg", Action: StabilityActionAllow, Expires: now.Add(-time.Hour)},
+		{Kind: VersionKindPseudo, Action: StabilityActionFail, Reason: "depend on a tagged release"},
+		{Kind: VersionKindPrerelease, Action: StabilityActionWarn},
+		{Kind: VersionKindIncompatible, Action: StabilityActionWarn},
+	}
+	failures, warnings := stabilityViolations(rules, buildList, map[string]string{
+		"github.com/other/pkg": "go.mod:9: require github.com/other/pkg v0.0.0-20240102030405-abcdefabcdef",
+	}, now)
+	assert.Equal(t, []string{
+		"github.com/other/pkg@v0.0.0-20240102030405-abcdefabcdef is a pseudo-version: depend on a tagged release (go.mod:9: require github.com/other/pkg v0.0.0-20240102030405-abcdefabcdef)",
+		"github.com/old/pkg@v1.2.4-0.20240102030405-abcdefabcdef is a pseudo-version: depend on a tagged release",
+	}, failures)
+	assert.Equal(t, []string{
+		`the rule "allow pseudo-version for github.com/old/pkg" expired on 2026-06-01`,
+		"github.com/docker/docker@v20.10.0+incompatible is a +incompatible version",
+		"github.com/forked/pkg@v1.1.0-beta.1 is a pre-release version",
+	}, warnings)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var toolchainNameRegexp = regexp.MustCompile(`^go\d+(\.\d+)*((rc|beta)\d+)?(-\S+)?$`)
+
+// ValidateGoToolchain returns an error if the provided value is not a valid value for the GOTOOLCHAIN environment
+// variable. Valid values are "auto", "local", "path", a toolchain name such as "go1.22.5" and a toolchain name followed
+// by "+auto" or "+path". The empty string is valid and specifies that GOTOOLCHAIN is not set.
+func ValidateGoToolchain(in string) error {
+	switch in {
+	case "", "auto", "local", "path":
+		return nil
+	}
+	name, _ := strings.CutSuffix(in, "+auto")
+	name, _ = strings.CutSuffix(name, "+path")
+	if !toolchainNameRegexp.MatchString(name) {
+		return errors.Errorf(`%q is not a valid GOTOOLCHAIN value: must be "auto", "local", "path" or a toolchain name such as "go1.22.5" optionally followed by "+auto" or "+path"`, in)
+	}
+	return nil
+}
+
+// pinnedToolchain returns the name of the toolchain selected by the provided GOTOOLCHAIN value (for example, "go1.22.5"
+// for "go1.22.5+auto"). Returns the empty string if the value does not name a specific toolchain.
+func pinnedToolchain(goToolchain string) string {
+	switch goToolchain {
+	case "", "auto", "local", "path":
+		return ""
+	}
+	name, _ := strings.CutSuffix(goToolchain, "+auto")
+	name, _ = strings.CutSuffix(name, "+path")
+	return name
+}
+
+// toolchainEnv returns the environment variables that select the toolchain used by the go commands. In verify mode,
+// GOTOOLCHAIN is always "local" so that the go command never switches to (or downloads) a toolchain other than the
+// one being verified. Otherwise, GOTOOLCHAIN is set to the configured value, if any.
+func toolchainEnv(verify bool, params ToolchainParams) []string {
+	if verify {
+		return []string{"GOTOOLCHAIN=local"}
+	}
+	if params.GoToolchain != "" {
+		return []string{"GOTOOLCHAIN=" + params.GoToolchain}
+	}
+	return nil
+}
+
+// envExecutor is an Executor that adds environment variables to every command run by the wrapped Executor. Variables
+// specified by the command itself take precedence.
+type envExecutor struct {
+	Executor
+	env []string
+}
+
+func (e envExecutor) Run(ctx context.Context, cmd Command) error {
+	cmd.Env = append(append([]string(nil), e.env...), cmd.Env...)
+	return e.Executor.Run(ctx, cmd)
+}
+
+// verifyToolchain returns a description of every mismatch between the running toolchain and the toolchain required by
+// the configuration and by the toolchain directives of the provided modules and workspace. The toolchain pinned by the
+// configuration must match the running toolchain exactly, while a toolchain directive only specifies the minimum
+// toolchain, so a newer running toolchain satisfies it. The running toolchain is only determined if a toolchain is
+// required.
+func verifyToolchain(ctx context.Context, params Params, modules []module, ws *workspace) ([]string, error) {
+	type requirement struct {
+		source    string
+		toolchain string
+		exact     bool
+	}
+	var requirements []requirement
+	if pinned := pinnedToolchain(params.Toolchain.GoToolchain); pinned != "" {
+		requirements = append(requirements, requirement{source: "toolchain.go-toolchain in the mod-plugin configuration", toolchain: pinned, exact: true})
+	}
+	type goFile struct {
+		path    string
+		relPath string
+	}
+	var files []goFile
+	for _, mod := range modules {
+		files = append(files, goFile{path: filepath.Join(mod.Dir, "go.mod"), relPath: mod.relPath("go.mod")})
+	}
+	if ws != nil {
+		files = append(files, goFile{path: filepath.Join(ws.Dir, "go.work"), relPath: "go.work"})
+	}
+	for _, file := range files {
+		directives, err := readGoDirectives(file.path)
+		if err != nil {
+			return nil, err
+		}
+		if directives.Toolchain != "" {
+			requirements = append(requirements, requirement{source: fmt.Sprintf("the toolchain directive in %s", file.relPath), toolchain: directives.Toolchain})
+		}
+	}
+	if len(requirements) == 0 {
+		return nil, nil
+	}
+
+	running, err := runningToolchain(ctx, params.executor())
+	if err != nil {
+		return nil, err
+	}
+	var failures []string
+	for _, req := range requirements {
+		switch {
+		case req.exact && req.toolchain != running:
+			failures = append(failures, fmt.Sprintf("%s requires toolchain %s, but the running toolchain is %s (GOTOOLCHAIN=local)", req.source, req.toolchain, running))
+		case !req.exact && toolchainOlder(running, req.toolchain):
+			failures = append(failures, fmt.Sprintf("%s requires toolchain %s or newer, but the running toolchain is %s (GOTOOLCHAIN=local)", req.source, req.toolchain, running))
+		}
+	}
+	return failures, nil
+}
+
+// toolchainOlder returns true if the toolchain named running is older than the toolchain named required. If either name
+// is not a valid Go version (such as a development build), only an identical name is considered to be at least as new.
+func toolchainOlder(running, required string) bool {
+	if !version.IsValid(running) || !version.IsValid(required) {
+		return running != required
+	}
+	return version.Compare(running, required) < 0
+}
+
+// runningToolchain returns the name of the toolchain run by executor (for example, "go1.22.5"). The command is run
+// outside of any module so that the result does not depend on the go or toolchain directives of the project.
+func runningToolchain(ctx context.Context, executor Executor) (string, error) {
+	env, err := goEnv(ctx, executor, os.TempDir(), "GOVERSION")
+	if err != nil {
+		return "", err
+	}
+	// GOVERSION may contain additional information such as enabled experiments after the version
+	fields := strings.Fields(env["GOVERSION"])
+	if len(fields) == 0 {
+		return "", errors.Errorf("go env did not report a value for GOVERSION")
+	}
+	return fields[0], nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGoToolchain(t *testing.T) {
+	for _, valid := range []string{"", "auto", "local", "path", "go1.22.5", "go1.21", "go1.23rc1", "go1.22.5+auto", "go1.22.5+path", "go1.22.5-custom"} {
+		assert.NoError(t, ValidateGoToolchain(valid), valid)
+	}
+	for _, invalid := range []string{"1.22.5", "go", "latest", "go1.22.5+local", "auto+path"} {
+		assert.Error(t, ValidateGoToolchain(invalid), invalid)
+	}
+}
+
+func TestPinnedToolchain(t *testing.T) {
+	for in, want := range map[string]string{
+		"":              "",
+		"auto":          "",
+		"local":         "",
+		"go1.22.5":      "go1.22.5",
+		"go1.22.5+auto": "go1.22.5",
+		"go1.22.5+path": "go1.22.5",
+	} {
+		assert.Equal(t, want, pinnedToolchain(in), in)
+	}
+}
+
+func TestRunVerifyToolchain(t *testing.T) {
+	for i, tc := range []struct {
+		name        string
+		toolchain   string
+		goToolchain string
+		goVersion   string
+		wantErr     string
+	}{
+		{
+			name:      "no toolchain requirements",
+			goVersion: "go1.23.0",
+		},
+		{
+			name:      "toolchain directive matches running toolchain",
+			toolchain: "go1.22.5",
+			goVersion: "go1.22.5 X:nocoverageredesign",
+		},
+		{
+			name:      "running toolchain is newer than toolchain directive",
+			toolchain: "go1.22.5",
+			goVersion: "go1.23.0",
+		},
+		{
+			name:      "running toolchain is older than toolchain directive",
+			toolchain: "go1.22.5",
+			goVersion: "go1.22.1",
+			wantErr:   "the toolchain directive in go.mod requires toolchain go1.22.5 or newer, but the running toolchain is go1.22.1 (GOTOOLCHAIN=local)",
+		},
+		{
+			name:      "development toolchain does not satisfy toolchain directive",
+			toolchain: "go1.22.5",
+			goVersion: "devel go1.23-abcdef",
+			wantErr:   "the toolchain directive in go.mod requires toolchain go1.22.5 or newer, but the running toolchain is devel (GOTOOLCHAIN=local)",
+		},
+		{
+			name:        "pinned toolchain does not match running toolchain",
+			goToolchain: "go1.22.5+auto",
+			goVersion:   "go1.23.0",
+			wantErr:     "toolchain.go-toolchain in the mod-plugin configuration requires toolchain go1.22.5, but the running toolchain is go1.23.0 (GOTOOLCHAIN=local)",
+		},
+	} {
+		projectDir := newTestModule(t, false)
+		goMod := testGoMod
+		if tc.toolchain != "" {
+			goMod = strings.Replace(goMod, "go 1.22\n", "go 1.22\n\ntoolchain "+tc.toolchain+"\n", 1)
+			require.NoError(t, os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte(goMod), 0644), "Case %d: %s", i, tc.name)
+		}
+		toolchain := newUpToDateToolchain()
+		toolchain.tidyGoMod = goMod
+		toolchain.goVersion = tc.goVersion
+
+		err := Run(context.Background(), projectDir, true, Params{
+			Toolchain: ToolchainParams{GoToolchain: tc.goToolchain},
+			Executor:  toolchain,
+		}, &bytes.Buffer{})
+		if tc.wantErr == "" {
+			assert.NoError(t, err, "Case %d: %s", i, tc.name)
+		} else {
+			assert.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+		}
+		assert.True(t, toolchain.allCommandsHaveEnv("GOTOOLCHAIN=local"), "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestRunApplyUsesConfiguredGoToolchain(t *testing.T) {
+	projectDir := newTestModule(t, false)
+	toolchain := newUpToDateToolchain()
+
+	err := Run(context.Background(), projectDir, false, Params{
+		Toolchain: ToolchainParams{GoToolchain: "go1.22.5"},
+		Executor:  toolchain,
+	}, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.True(t, toolchain.allCommandsHaveEnv("GOTOOLCHAIN=go1.22.5"))
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"fmt"
+	"go/version"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+)
+
+// ValidateToolchainDirective returns an error if the provided value is not a valid value for the toolchain directive
+// of a go.mod file. The empty string is valid and specifies that the toolchain directive is not managed.
+func ValidateToolchainDirective(in string) error {
+	if in != "" && !toolchainNameRegexp.MatchString(in) {
+		return errors.Errorf("%q is not a valid toolchain name: must be a toolchain name such as \"go1.22.5\"", in)
+	}
+	return nil
+}
+
+// setToolchainDirective returns the provided content of a go.mod file with its toolchain directive set to toolchain or
+// removed if toolchain is redundant with the go directive. The file is edited using modfile so that the rest of its
+// formatting and comments are preserved. The returned description describes the problem with the current toolchain
+// directive and is empty if the directive is already up-to-date, in which case goMod is returned unmodified.
+func setToolchainDirective(relPath string, goMod []byte, toolchain string) ([]byte, string, error) {
+	modFile, err := modfile.Parse(relPath, goMod, nil)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to parse %s", relPath)
+	}
+	var current, goVersion string
+	if modFile.Toolchain != nil {
+		current = modFile.Toolchain.Name
+	}
+	if modFile.Go != nil {
+		goVersion = modFile.Go.Version
+	}
+
+	// the toolchain is redundant if it is not newer than the go directive because the go command selects a toolchain
+	// that is at least as new as the go directive anyway. A toolchain with a custom suffix (such as "go1.22.5-custom")
+	// is never redundant.
+	redundant := goVersion != "" && !strings.Contains(toolchain, "-") && version.Compare(toolchain, "go"+goVersion) <= 0
+	var description string
+	if redundant {
+		if current == "" {
+			return goMod, "", nil
+		}
+		description = fmt.Sprintf("the toolchain directive %s is redundant with the go directive %s and should be removed", current, goVersion)
+		modFile.DropToolchainStmt()
+	} else {
+		switch current {
+		case toolchain:
+			return goMod, "", nil
+		case "":
+			description = fmt.Sprintf("the toolchain directive is missing, expected toolchain %s", toolchain)
+		default:
+			description = fmt.Sprintf("the toolchain directive is %s, expected %s", current, toolchain)
+		}
+		if err := modFile.AddToolchainStmt(toolchain); err != nil {
+			return nil, "", errors.Wrapf(err, "failed to set toolchain directive of %s", relPath)
+		}
+	}
+	modFile.Cleanup()
+	updated, err := modFile.Format()
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to format %s", relPath)
+	}
+	return updated, description, nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetToolchainDirective(t *testing.T) {
+	for i, tc := range []struct {
+		name            string
+		goMod           string
+		toolchain       string
+		wantGoMod       string
+		wantDescription string
+	}{
+		{
+			name:      "up-to-date",
+			goMod:     "module github.com/mod/test\n\ngo 1.22.0\n\ntoolchain go1.22.5\n",
+			toolchain: "go1.22.5",
+			wantGoMod: "module github.com/mod/test\n\ngo 1.22.0\n\ntoolchain go1.22.5\n",
+		},
+		{
+			name:            "missing",
+			goMod:           "// Module comment\nmodule github.com/mod/test\n\ngo 1.22.0 // go comment\n\nrequire github.com/pkg/errors v0.9.1\n",
+			toolchain:       "go1.22.5",
+			wantGoMod:       "// Module comment\nmodule github.com/mod/test\n\ngo 1.22.0 // go comment\n\ntoolchain go1.22.5\n\nrequire github.com/pkg/errors v0.9.1\n",
+			wantDescription: "the toolchain directive is missing, expected toolchain go1.22.5",
+		},
+		{
+			name:            "out of date",
+			goMod:           "module github.com/mod/test\n\ngo 1.22.0\n\ntoolchain go1.22.4\n",
+			toolchain:       "go1.22.5",
+			wantGoMod:       "module github.com/mod/test\n\ngo 1.22.0\n\ntoolchain go1.22.5\n",
+			wantDescription: "the toolchain directive is go1.22.4, expected go1.22.5",
+		},
+		{
+			name:            "redundant with go directive",
+			goMod:           "module github.com/mod/test\n\ngo 1.23.0\n\ntoolchain go1.22.5\n\nrequire github.com/pkg/errors v0.9.1\n",
+			toolchain:       "go1.22.5",
+			wantGoMod:       "module github.com/mod/test\n\ngo 1.23.0\n\nrequire github.com/pkg/errors v0.9.1\n",
+			wantDescription: "the toolchain directive go1.22.5 is redundant with the go directive 1.23.0 and should be removed",
+		},
+		{
+			name:      "redundant and absent",
+			goMod:     "module github.com/mod/test\n\ngo 1.22.5\n",
+			toolchain: "go1.22.5",
+			wantGoMod: "module github.com/mod/test\n\ngo 1.22.5\n",
+		},
+		{
+			name:            "release is newer than language version",
+			goMod:           "module github.com/mod/test\n\ngo 1.22\n",
+			toolchain:       "go1.22.0",
+			wantGoMod:       "module github.com/mod/test\n\ngo 1.22\n\ntoolchain go1.22.0\n",
+			wantDescription: "the toolchain directive is missing, expected toolchain go1.22.0",
+		},
+		{
+			name:            "release is newer than release candidate",
+			goMod:           "module github.com/mod/test\n\ngo 1.23rc1\n",
+			toolchain:       "go1.23.0",
+			wantGoMod:       "module github.com/mod/test\n\ngo 1.23rc1\n\ntoolchain go1.23.0\n",
+			wantDescription: "the toolchain directive is missing, expected toolchain go1.23.0",
+		},
+		{
+			name:            "custom toolchain is never redundant",
+			goMod:           "module github.com/mod/test\n\ngo 1.22.5\n",
+			toolchain:       "go1.22.5-custom",
+			wantGoMod:       "module github.com/mod/test\n\ngo 1.22.5\n\ntoolchain go1.22.5-custom\n",
+			wantDescription: "the toolchain directive is missing, expected toolchain go1.22.5-custom",
+		},
+	} {
+		goMod, description, err := setToolchainDirective("go.mod", []byte(tc.goMod), tc.toolchain)
+		require.NoError(t, err, "Case %d: %s", i, tc.name)
+		assert.Equal(t, tc.wantGoMod, string(goMod), "Case %d: %s", i, tc.name)
+		assert.Equal(t, tc.wantDescription, description, "Case %d: %s", i, tc.name)
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// UpgradeStrategy specifies the versions that dependencies are upgraded to.
+type UpgradeStrategy string
+
+const (
+	// UpgradeStrategyPatch upgrades dependencies to the latest patch version of their current minor version.
+	UpgradeStrategyPatch UpgradeStrategy = "patch"
+	// UpgradeStrategyMinor upgrades dependencies to the latest version with the same module path, which may be a newer
+	// minor version.
+	UpgradeStrategyMinor UpgradeStrategy = "minor"
+	// UpgradeStrategyMajor upgrades dependencies to their latest newer major version (such as "example.com/mod/v3"),
+	// which has a different module path, and rewrites the import paths of the packages of the dependencies in the Go
+	// source files of the project. Because the APIs of the packages may have changed, only the modules that are
+	// specified explicitly are upgraded.
+	UpgradeStrategyMajor UpgradeStrategy = "major"
+)
+
+// ParseUpgradeStrategy returns the UpgradeStrategy represented by the provided string.
+func ParseUpgradeStrategy(in string) (UpgradeStrategy, error) {
+	switch strategy := UpgradeStrategy(in); strategy {
+	case UpgradeStrategyPatch, UpgradeStrategyMinor, UpgradeStrategyMajor:
+		return strategy, nil
+	default:
+		return "", errors.Errorf("%q is not a valid upgrade strategy: must be %q, %q or %q", in, UpgradeStrategyPatch, UpgradeStrategyMinor, UpgradeStrategyMajor)
+	}
+}
+
+// UpgradeParams specifies the behavior of Upgrade.
+type UpgradeParams struct {
+	// Exclude are the module patterns (see MatchModulePattern) for the modules that are never upgraded directly. A
+	// module that is excluded may still be upgraded if an upgraded module requires a newer version of it.
+	Exclude []string
+	// BuildCheck specifies that "go build ./..." is run for every module after the upgrade. If it fails, the upgrade is
+	// rolled back.
+	BuildCheck bool
+}
+
+// VersionChange is a change of the version of a module in the build list of a module in the project.
+type VersionChange struct {
+	// Module is the path of the module in the project whose build list changed.
+	Module string `json:"module"`
+	// Path is the path of the module whose version changed.
+	Path string `json:"path"`
+	// Before is the version before the change. It is empty if the module was added to the build list.
+	Before string `json:"before,omitempty"`
+	// After is the version after the change. It is empty if the module was removed from the build list.
+	After string `json:"after,omitempty"`
+}
+
+// String returns a description of the change such as "github.com/pkg/errors v0.9.1 => v0.9.3".
+func (c VersionChange) String() string {
+	switch {
+	case c.Before == "":
+		return fmt.Sprintf("%s %s (added)", c.Path, c.After)
+	case c.After == "":
+		return fmt.Sprintf("%s %s (removed)", c.Path, c.Before)
+	default:
+		return fmt.Sprintf("%s %s => %s", c.Path, c.Before, c.After)
+	}
+}
+
+// Upgrade upgrades the dependencies of every module in projectDir according to the provided strategy using "go get"
+// and then runs the same operations as Run to tidy (and, if enabled, vendor) every module. If modPatterns is
+// non-empty, only the modules that match one of the patterns (see MatchModulePattern) are upgraded. The
+// UpgradeStrategyMajor strategy requires modPatterns to be non-empty and also rewrites the import paths of the
+// upgraded modules in the Go source files of every module. Modules that are replaced by a replace directive and modules
+// excluded by params.Upgrade.Exclude are not upgraded.
+//
+// If any of the operations (or the build check specified by params.Upgrade.BuildCheck) fails, the go.mod, go.sum and
+// vendor paths of every module, the Go source files whose import paths were rewritten and the go.work and go.work.sum
+// files and vendor directory of the workspace (if any) are restored to the state they were in before Upgrade was
+// called. The summary of every version change is written to stdout and returned.
+func Upgrade(ctx context.Context, projectDir string, strategy UpgradeStrategy, modPatterns []string, params Params, stdout io.Writer) (rChanges []VersionChange, rErr error) {
+	if strategy == UpgradeStrategyMajor && len(modPatterns) == 0 {
+		return nil, errors.Errorf("the %q upgrade strategy requires the modules to upgrade to be specified", strategy)
+	}
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine absolute path of project directory")
+	}
+	modules, err := discoverModules(projectDir, params.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := readWorkspace(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	// Run selects the toolchain itself, so it is provided with the original parameters
+	runParams := params
+	if env := toolchainEnv(false, params.Toolchain); len(env) > 0 {
+		params.Executor = envExecutor{Executor: params.executor(), env: env}
+	}
+
+	var modFilePaths []string
+	vendorDirs := make(map[string]struct{})
+	for _, mod := range modules {
+		modFilePaths = append(modFilePaths, filepath.Join(mod.Dir, "go.mod"), filepath.Join(mod.Dir, "go.sum"))
+		vendorDirs[filepath.Join(mod.Dir, "vendor")] = struct{}{}
+	}
+	if ws != nil {
+		modFilePaths = append(modFilePaths, filepath.Join(ws.Dir, "go.work"), filepath.Join(ws.Dir, "go.work.sum"))
+		vendorDirs[filepath.Join(ws.Dir, "vendor")] = struct{}{}
+	}
+	snapshot, err := newFileSnapshot(modFilePaths...)
+	if err != nil {
+		return nil, err
+	}
+	// sourceSnapshots record the Go source files whose import paths are rewritten by major version upgrades
+	var sourceSnapshots []*fileSnapshot
+	var backups []*vendorBackup
+	defer func() {
+		for _, backup := range backups {
+			if err := backup.cleanup(); err != nil && rErr == nil {
+				rErr = err
+			}
+		}
+	}()
+	for _, vendorDir := range slices.Sorted(maps.Keys(vendorDirs)) {
+		backup, err := newVendorBackup(vendorDir)
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, backup)
+	}
+	defer func() {
+		if rErr == nil {
+			return
+		}
+		rollbackErrs := []error{errors.Wrapf(rErr, "upgrade was rolled back")}
+		for _, snapshot := range append([]*fileSnapshot{snapshot}, sourceSnapshots...) {
+			if err := snapshot.restore(); err != nil {
+				rollbackErrs = append(rollbackErrs, err)
+			}
+		}
+		for _, backup := range backups {
+			if err := backup.restore(); err != nil {
+				rollbackErrs = append(rollbackErrs, err)
+			}
+		}
+		rChanges, rErr = nil, joinErrors(rollbackErrs)
+	}()
+
+	buildListsBefore := make([][]buildListModule, len(modules))
+	for i, mod := range modules {
+		if buildListsBefore[i], err = listModules(ctx, params.executor(), mod.Dir, "-mod=readonly", "-u", "all"); err != nil {
+			return nil, &ModuleError{Module: mod.Path, Err: err}
+		}
+		targets := upgradeTargets(buildListsBefore[i], strategy, modPatterns, params.Upgrade.Exclude)
+		if strategy == UpgradeStrategyMajor {
+			upgrades, err := majorUpgrades(ctx, params.executor(), mod.Dir, buildListsBefore[i], modPatterns, params.Upgrade.Exclude)
+			if err != nil {
+				return nil, &ModuleError{Module: mod.Path, Err: err}
+			}
+			rewrites, err := rewriteImports(mod.Dir, buildListsBefore[i], upgrades)
+			if err != nil {
+				return nil, &ModuleError{Module: mod.Path, Err: err}
+			}
+			var rewritePaths []string
+			for _, rewrite := range rewrites {
+				rewritePaths = append(rewritePaths, rewrite.path)
+			}
+			sourceSnapshot, err := newFileSnapshot(rewritePaths...)
+			if err != nil {
+				return nil, err
+			}
+			sourceSnapshots = append(sourceSnapshots, sourceSnapshot)
+			if err := (&pendingUpdate{files: rewrites}).write(); err != nil {
+				return nil, err
+			}
+			for _, upgrade := range upgrades {
+				targets = append(targets, upgrade.NewPath+"@"+upgrade.Version)
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		if err := run(ctx, params.executor(), mod.Dir, moduleEnv, stdout, append([]string{"get"}, targets...)...); err != nil {
+			return nil, &ModuleError{Module: mod.Path, Err: err}
+		}
+	}
+
+	if err := Run(ctx, projectDir, false, runParams, stdout); err != nil {
+		return nil, err
+	}
+	if params.Upgrade.BuildCheck {
+		for _, mod := range modules {
+			if err := run(ctx, params.executor(), mod.Dir, moduleEnv, stdout, "build", "./..."); err != nil {
+				return nil, &ModuleError{Module: mod.Path, Err: err}
+			}
+		}
+	}
+
+	var changes []VersionChange
+	for i, mod := range modules {
+		buildListAfter, err := listModules(ctx, params.executor(), mod.Dir, "-mod=readonly", "all")
+		if err != nil {
+			return nil, &ModuleError{Module: mod.Path, Err: err}
+		}
+		changes = append(changes, versionChanges(mod.Path, buildListsBefore[i], buildListAfter)...)
+	}
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(stdout, "no module versions changed")
+	}
+	for _, change := range changes {
+		_, _ = fmt.Fprintf(stdout, "%s: %s\n", change.Module, change)
+	}
+	return changes, nil
+}
+
+// upgradeTargets returns the arguments for "go get" that upgrade the modules in the provided build list (as reported by
+// "go list -m -u") according to the patch or minor strategy (see upgradeCandidates for the modules that are upgraded).
+// Modules without a newer version are not upgraded. Returns nil for UpgradeStrategyMajor, whose targets are determined
+// by majorUpgrades.
+func upgradeTargets(buildList []buildListModule, strategy UpgradeStrategy, modPatterns, exclude []string) []string {
+	var targets []string
+	for _, mod := range upgradeCandidates(buildList, modPatterns, exclude) {
+		if mod.Update == nil {
+			continue
+		}
+		switch strategy {
+		case UpgradeStrategyPatch:
+			targets = append(targets, mod.Path+"@patch")
+		case UpgradeStrategyMinor:
+			targets = append(targets, mod.Path+"@"+mod.Update.Version)
+		}
+	}
+	return targets
+}
+
+// upgradeCandidates returns the modules in the provided build list that may be upgraded. If modPatterns is non-empty,
+// only the modules that match one of them are returned. The main module, replaced modules and modules that match one
+// of the exclude patterns are never returned.
+func upgradeCandidates(buildList []buildListModule, modPatterns, exclude []string) []buildListModule {
+	var candidates []buildListModule
+	for _, mod := range buildList {
+		if mod.Main || mod.Replace != nil || matchesAnyModulePattern(exclude, mod.Path) {
+			continue
+		}
+		if len(modPatterns) > 0 && !matchesAnyModulePattern(modPatterns, mod.Path) {
+			continue
+		}
+		candidates = append(candidates, mod)
+	}
+	return candidates
+}
+
+// versionChanges returns the changes from the build list before to the build list after for the module with the
+// provided path, with the modules in the order of the build list after followed by the removed modules.
+func versionChanges(modPath string, before, after []buildListModule) []VersionChange {
+	versionsBefore := make(map[string]string)
+	for _, mod := range before {
+		if !mod.Main {
+			versionsBefore[mod.Path] = mod.Version
+		}
+	}
+	var changes []VersionChange
+	for _, mod := range after {
+		if mod.Main {
+			continue
+		}
+		if versionBefore := versionsBefore[mod.Path]; versionBefore != mod.Version {
+			changes = append(changes, VersionChange{Module: modPath, Path: mod.Path, Before: versionBefore, After: mod.Version})
+		}
+		delete(versionsBefore, mod.Path)
+	}
+	for _, mod := range before {
+		if _, ok := versionsBefore[mod.Path]; ok {
+			changes = append(changes, VersionChange{Module: modPath, Path: mod.Path, Before: mod.Version})
+		}
+	}
+	return changes
+}
+
+// vendorBackup is a copy of a vendor directory that can be restored later. The copy is stored in a temporary
+// directory outside of the project so that the project never contains files that are not part of it.
+type vendorBackup struct {
+	vendorDir  string
+	tmpDir     string
+	backupPath string
+}
+
+// newVendorBackup copies the vendor directory at vendorDir (if it exists) to a temporary directory.
+func newVendorBackup(vendorDir string) (*vendorBackup, error) {
+	tmpDir, err := os.MkdirTemp("", "godel-mod-plugin-vendor-backup-")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create temporary directory")
+	}
+	backup := &vendorBackup{
+		vendorDir:  vendorDir,
+		tmpDir:     tmpDir,
+		backupPath: filepath.Join(tmpDir, "vendor"),
+	}
+	if dirExists(backup.vendorDir) {
+		if err := os.CopyFS(backup.backupPath, os.DirFS(backup.vendorDir)); err != nil {
+			_ = os.RemoveAll(tmpDir)
+			return nil, errors.Wrapf(err, "failed to copy %s to %s", backup.vendorDir, backup.backupPath)
+		}
+	}
+	return backup, nil
+}
+
+// restore replaces the vendor directory with the copy, removing it if it did not exist when the copy was made. The
+// temporary directory may be on a different file system than the vendor directory, so the copy is copied next to the
+// vendor directory first so that it can be moved into place with a rename.
+func (b *vendorBackup) restore() (rErr error) {
+	if !dirExists(b.backupPath) {
+		return replaceDir(b.backupPath, b.vendorDir)
+	}
+	restoreDir, err := os.MkdirTemp(filepath.Dir(b.vendorDir), ".vendor-restore-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary directory")
+	}
+	defer func() {
+		if err := os.RemoveAll(restoreDir); err != nil && rErr == nil {
+			rErr = errors.Wrapf(err, "failed to remove temporary directory %s", restoreDir)
+		}
+	}()
+	restorePath := filepath.Join(restoreDir, "vendor")
+	if err := os.CopyFS(restorePath, os.DirFS(b.backupPath)); err != nil {
+		return errors.Wrapf(err, "failed to copy %s to %s", b.backupPath, restorePath)
+	}
+	return replaceDir(restorePath, b.vendorDir)
+}
+
+// cleanup removes the temporary directory that contains the copy.
+func (b *vendorBackup) cleanup() error {
+	if err := os.RemoveAll(b.tmpDir); err != nil {
+		return errors.Wrapf(err, "failed to remove temporary directory %s", b.tmpDir)
+	}
+	return nil
+}
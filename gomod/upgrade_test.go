@@ -0,0 +1,219 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUpgradeStrategy(t *testing.T) {
+	for i, tc := range []struct {
+		in      string
+		want    UpgradeStrategy
+		wantErr string
+	}{
+		{in: "patch", want: UpgradeStrategyPatch},
+		{in: "minor", want: UpgradeStrategyMinor},
+		{in: "major", want: UpgradeStrategyMajor},
+		{in: "latest", wantErr: `"latest" is not a valid upgrade strategy: must be "patch", "minor" or "major"`},
+	} {
+		got, err := ParseUpgradeStrategy(tc.in)
+		if tc.wantErr != "" {
+			assert.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.in)
+			continue
+		}
+		require.NoError(t, err, "Case %d: %s", i, tc.in)
+		assert.Equal(t, tc.want, got, "Case %d: %s", i, tc.in)
+	}
+}
+
+func TestUpgradeTargets(t *testing.T) {
+	buildList := []buildListModule{
+		{Path: "github.com/mod/test", Main: true},
+		{Path: "github.com/pkg/errors", Version: "v0.9.1", Update: &buildListModule{Path: "github.com/pkg/errors", Version: "v0.10.0"}},
+		{Path: "github.com/palantir/pkg", Version: "v1.0.0", Update: &buildListModule{Path: "github.com/palantir/pkg", Version: "v1.1.0"}},
+		{Path: "github.com/forked/pkg", Version: "v1.0.0", Replace: &buildListModule{Path: "../pkg"}, Update: &buildListModule{Path: "github.com/forked/pkg", Version: "v1.1.0"}},
+		{Path: "github.com/current/pkg", Version: "v1.0.0"},
+		{Path: "github.com/excluded/pkg", Version: "v1.0.0", Update: &buildListModule{Path: "github.com/excluded/pkg", Version: "v1.1.0"}},
+	}
+	exclude := []string{"github.com/excluded/..."}
+	for i, tc := range []struct {
+		name        string
+		strategy    UpgradeStrategy
+		modPatterns []string
+		want        []string
+	}{
+		{
+			name:     "patch",
+			strategy: UpgradeStrategyPatch,
+			want:     []string{"github.com/pkg/errors@patch", "github.com/palantir/pkg@patch"},
+		},
+		{
+			name:     "minor",
+			strategy: UpgradeStrategyMinor,
+			want:     []string{"github.com/pkg/errors@v0.10.0", "github.com/palantir/pkg@v1.1.0"},
+		},
+		{
+			name:        "module patterns",
+			strategy:    UpgradeStrategyMinor,
+			modPatterns: []string{"github.com/palantir/...", "github.com/excluded/pkg"},
+			want:        []string{"github.com/palantir/pkg@v1.1.0"},
+		},
+		{
+			name:        "major versions are upgraded separately",
+			strategy:    UpgradeStrategyMajor,
+			modPatterns: []string{"github.com/pkg/errors"},
+		},
+	} {
+		assert.Equal(t, tc.want, upgradeTargets(buildList, tc.strategy, tc.modPatterns, exclude), "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestVersionChanges(t *testing.T) {
+	before := []buildListModule{
+		{Path: "github.com/mod/test", Main: true},
+		{Path: "github.com/pkg/errors", Version: "v0.9.1"},
+		{Path: "github.com/old/pkg", Version: "v1.0.0"},
+		{Path: "github.com/same/pkg", Version: "v1.0.0"},
+	}
+	after := []buildListModule{
+		{Path: "github.com/mod/test", Main: true},
+		{Path: "github.com/new/pkg", Version: "v1.2.0"},
+		{Path: "github.com/pkg/errors", Version: "v0.9.3"},
+		{Path: "github.com/same/pkg", Version: "v1.0.0"},
+	}
+	changes := versionChanges("github.com/mod/test", before, after)
+	var descriptions []string
+	for _, change := range changes {
+		descriptions = append(descriptions, change.String())
+	}
+	assert.Equal(t, []string{
+		"github.com/new/pkg v1.2.0 (added)",
+		"github.com/pkg/errors v0.9.1 => v0.9.3",
+		"github.com/old/pkg v1.0.0 (removed)",
+	}, descriptions)
+}
+
+func TestUpgradeBuildCheckFailureRollsBack(t *testing.T) {
+	projectDir := newTestModule(t, true)
+	toolchain := newUpToDateToolchain()
+	toolchain.listOutput = `{"Path": "github.com/mod/test", "Main": true}
+{"Path": "github.com/pkg/errors", "Version": "v0.9.1", "Update": {"Path": "github.com/pkg/errors", "Version": "v0.9.3"}}
+`
+	toolchain.tidyGoMod = "module github.com/mod/test\n\ngo 1.22\n\nrequire github.com/pkg/errors v0.9.3\n"
+	toolchain.vendorFiles = map[string]string{
+		"modules.txt":                     "# github.com/pkg/errors v0.9.3\n## explicit\ngithub.com/pkg/errors\n",
+		"github.com/pkg/errors/errors.go": "package errors // v0.9.3\n",
+	}
+	toolchain.failArgs = "build"
+
+	params := Params{
+		Upgrade:  UpgradeParams{BuildCheck: true},
+		Executor: toolchain,
+	}
+	_, err := Upgrade(context.Background(), projectDir, UpgradeStrategyPatch, nil, params, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "upgrade was rolled back")
+	assert.True(t, toolchain.ranCommand("get github.com/pkg/errors@patch"))
+	assert.True(t, toolchain.ranCommand("mod vendor"))
+
+	for name, want := range map[string]string{
+		"go.mod":                                 testGoMod,
+		"go.sum":                                 testGoSum,
+		"vendor/modules.txt":                     testModulesTxt,
+		"vendor/github.com/pkg/errors/errors.go": testVendoredSrc,
+	} {
+		got, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(name)))
+		require.NoError(t, err, name)
+		assert.Equal(t, want, string(got), name)
+	}
+
+	// temporary directories are removed
+	entries, err := os.ReadDir(projectDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"go.mod", "go.sum", "vendor"}, names)
+}
+
+func TestUpgradeRollsBackWorkspace(t *testing.T) {
+	projectDir := newTestModule(t, false)
+	writeTestFiles(projectDir, map[string]string{
+		"go.work":                 "go 1.22\n\nuse .\n",
+		"go.work.sum":             "github.com/foo/bar v1.0.0 h1:old=\n",
+		"vendor/modules.txt":      testModulesTxt,
+		"vendor/workspace.go.txt": "old\n",
+	})
+	toolchain := newUpToDateToolchain()
+	toolchain.listOutput = `{"Path": "github.com/mod/test", "Main": true}
+{"Path": "github.com/pkg/errors", "Version": "v0.9.1", "Update": {"Path": "github.com/pkg/errors", "Version": "v0.9.3"}}
+`
+	toolchain.workSum = "github.com/foo/bar v1.0.0 h1:new=\n"
+	toolchain.failArgs = "build"
+
+	_, err := Upgrade(context.Background(), projectDir, UpgradeStrategyPatch, nil, Params{
+		VendorMode: VendorModeAlways,
+		Upgrade:    UpgradeParams{BuildCheck: true},
+		Executor:   toolchain,
+	}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "upgrade was rolled back")
+	assert.True(t, toolchain.ranCommand("work vendor"))
+
+	for name, want := range map[string]string{
+		"go.work.sum":             "github.com/foo/bar v1.0.0 h1:old=\n",
+		"vendor/workspace.go.txt": "old\n",
+	} {
+		got, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(name)))
+		require.NoError(t, err, name)
+		assert.Equal(t, want, string(got), name)
+	}
+	entries, err := os.ReadDir(projectDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"go.mod", "go.sum", "go.work", "go.work.sum", "vendor"}, names)
+}
+
+func TestUpgradeMajorRewritesImportsAndRollsBack(t *testing.T) {
+	projectDir := newTestModule(t, false)
+	const mainSrc = "package main\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/pkg/errors\"\n)\n\nfunc main() { fmt.Println(errors.New(\"x\")) }\n"
+	writeTestFiles(projectDir, map[string]string{"main.go": mainSrc})
+	toolchain := newUpToDateToolchain()
+	// the output is used both for the build list and for the queries of the newer major versions
+	toolchain.listOutput = `{"Path": "github.com/mod/test", "Main": true}
+{"Path": "github.com/pkg/errors", "Version": "v0.9.1"}
+{"Path": "github.com/pkg/errors/v2", "Version": "v2.0.0"}
+`
+	toolchain.failArgs = "build"
+
+	_, err := Upgrade(context.Background(), projectDir, UpgradeStrategyMajor, nil, Params{Executor: toolchain}, &bytes.Buffer{})
+	assert.EqualError(t, err, `the "major" upgrade strategy requires the modules to upgrade to be specified`)
+
+	_, err = Upgrade(context.Background(), projectDir, UpgradeStrategyMajor, []string{"github.com/pkg/errors"}, Params{
+		Upgrade:  UpgradeParams{BuildCheck: true},
+		Executor: toolchain,
+	}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "upgrade was rolled back")
+	assert.True(t, toolchain.ranCommand("list -m -json -mod=readonly -e github.com/pkg/errors/v2@latest"))
+	assert.True(t, toolchain.ranCommand("get github.com/pkg/errors/v2@v2.0.0"))
+
+	// the import paths were rewritten before running "go get" and are restored by the rollback
+	got, err := os.ReadFile(filepath.Join(projectDir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, mainSrc, string(got))
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"fmt"
+	"go/version"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+	modmodule "golang.org/x/mod/module"
+)
+
+// modulesTxtFailure verifies that the provided modules listed in the vendor/modules.txt file of the module are
+// consistent with the provided content of its go.mod file without running any go commands, which makes it possible to
+// report inconsistencies before the vendor directory is generated. Returns the verification failure message or the
+// empty string if the files are consistent.
+func modulesTxtFailure(mod module, goMod []byte, vendored []vendoredModule) (string, error) {
+	goModPath := filepath.Join(mod.Dir, "go.mod")
+	modFile, err := modfile.Parse(goModPath, goMod, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse %s", goModPath)
+	}
+	inconsistencies := modulesTxtInconsistencies(modFile, vendored)
+	if len(inconsistencies) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%s is inconsistent with %s:\n%s", mod.relPath("vendor/modules.txt"), mod.relPath("go.mod"), strings.Join(inconsistencies, "\n")), nil
+}
+
+// modulesTxtInconsistencies returns the inconsistencies between the provided go.mod file and the modules listed in
+// its vendor/modules.txt file, which mirror the checks performed by the go command when building with "-mod=vendor":
+//
+//   - every module required by go.mod is listed at the same version and marked as explicit
+//   - every module marked as explicit is required by go.mod
+//   - every replacement in go.mod is recorded and every recorded replacement is in go.mod
+//   - no module declares a newer go version than go.mod (if go.mod declares go 1.21 or later, which requires it)
+//
+// The markers and replacements are only recorded for go 1.14 and later, so no inconsistencies are returned for
+// modules that declare an earlier go version.
+func modulesTxtInconsistencies(modFile *modfile.File, vendored []vendoredModule) []string {
+	goVersion := "1.11"
+	if modFile.Go != nil {
+		goVersion = modFile.Go.Version
+	}
+	if version.Compare(version.Lang("go"+goVersion), "go1.14") < 0 {
+		return nil
+	}
+
+	byVersion := make(map[modmodule.Version]vendoredModule, len(vendored))
+	versionsByPath := make(map[string][]string)
+	for _, mod := range vendored {
+		byVersion[modmodule.Version{Path: mod.Path, Version: mod.Version}] = mod
+		if mod.Version != "" {
+			versionsByPath[mod.Path] = append(versionsByPath[mod.Path], mod.Version)
+		}
+	}
+
+	var inconsistencies []string
+	required := make(map[modmodule.Version]bool, len(modFile.Require))
+	for _, req := range modFile.Require {
+		required[req.Mod] = true
+		vendoredMod, ok := byVersion[req.Mod]
+		switch {
+		case ok && !vendoredMod.Explicit:
+			inconsistencies = append(inconsistencies, fmt.Sprintf("%s is required by go.mod, but is not marked as explicit in vendor/modules.txt", req.Mod))
+		case !ok && len(versionsByPath[req.Mod.Path]) > 0:
+			inconsistencies = append(inconsistencies, fmt.Sprintf("%s is required by go.mod, but vendor/modules.txt lists %s", req.Mod, strings.Join(versionsByPath[req.Mod.Path], ", ")))
+		case !ok:
+			inconsistencies = append(inconsistencies, fmt.Sprintf("%s is required by go.mod, but is not listed in vendor/modules.txt", req.Mod))
+		}
+	}
+	for _, mod := range vendored {
+		if mod.Explicit && !required[modmodule.Version{Path: mod.Path, Version: mod.Version}] {
+			inconsistencies = append(inconsistencies, fmt.Sprintf("%s is marked as explicit in vendor/modules.txt, but is not required by go.mod", moduleVersionString(mod.Path, mod.Version)))
+		}
+	}
+
+	for _, rep := range modFile.Replace {
+		var recorded []vendoredModule
+		for _, mod := range vendored {
+			if mod.Path == rep.Old.Path && (rep.Old.Version == "" || mod.Version == rep.Old.Version) {
+				recorded = append(recorded, mod)
+			}
+		}
+		old := moduleVersionString(rep.Old.Path, rep.Old.Version)
+		if len(recorded) == 0 {
+			inconsistencies = append(inconsistencies, fmt.Sprintf("%s is replaced in go.mod, but the replacement is not recorded in vendor/modules.txt", old))
+			continue
+		}
+		for _, mod := range recorded {
+			switch {
+			case mod.ReplacePath == "":
+				inconsistencies = append(inconsistencies, fmt.Sprintf("%s is replaced in go.mod, but is not marked as replaced in vendor/modules.txt", moduleVersionString(mod.Path, mod.Version)))
+			case mod.ReplacePath != rep.New.Path || mod.ReplaceVersion != rep.New.Version:
+				inconsistencies = append(inconsistencies, fmt.Sprintf("%s is replaced by %s in go.mod, but by %s in vendor/modules.txt",
+					moduleVersionString(mod.Path, mod.Version), moduleVersionString(rep.New.Path, rep.New.Version), moduleVersionString(mod.ReplacePath, mod.ReplaceVersion)))
+			}
+		}
+	}
+	for _, mod := range vendored {
+		if mod.ReplacePath != "" && !replacedInGoMod(modFile, mod) {
+			inconsistencies = append(inconsistencies, fmt.Sprintf("%s is marked as replaced in vendor/modules.txt, but is not replaced in go.mod", moduleVersionString(mod.Path, mod.Version)))
+		}
+	}
+
+	// starting with go 1.21, the go version of the main module must be at least the go version of every dependency
+	if version.Compare(version.Lang("go"+goVersion), "go1.21") >= 0 {
+		for _, mod := range vendored {
+			if mod.GoVersion != "" && version.Compare("go"+mod.GoVersion, "go"+goVersion) > 0 {
+				inconsistencies = append(inconsistencies, fmt.Sprintf("%s requires go %s according to vendor/modules.txt, but go.mod declares go %s", moduleVersionString(mod.Path, mod.Version), mod.GoVersion, goVersion))
+			}
+		}
+	}
+	sort.Strings(inconsistencies)
+	return inconsistencies
+}
+
+// replacedInGoMod returns true if go.mod contains a replacement that applies to the provided vendored module.
+func replacedInGoMod(modFile *modfile.File, mod vendoredModule) bool {
+	for _, rep := range modFile.Replace {
+		if rep.Old.Path == mod.Path && (rep.Old.Version == "" || rep.Old.Version == mod.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleVersionString returns "<path>@<version>" or only the path if version is empty.
+func moduleVersionString(path, modVersion string) string {
+	if modVersion == "" {
+		return path
+	}
+	return path + "@" + modVersion
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/modfile"
+)
+
+func TestModulesTxtInconsistencies(t *testing.T) {
+	for i, tc := range []struct {
+		name       string
+		goMod      string
+		modulesTxt string
+		want       []string
+	}{
+		{
+			name: "consistent",
+			goMod: `module github.com/test/mod
+
+go 1.21
+
+require github.com/pkg/errors v0.9.1
+
+replace github.com/old/mod => ../old
+`,
+			modulesTxt: `# github.com/pkg/errors v0.9.1
+## explicit; go 1.20
+github.com/pkg/errors
+# github.com/old/mod => ../old
+`,
+		},
+		{
+			name: "version differs",
+			goMod: `module github.com/test/mod
+
+go 1.21
+
+require github.com/pkg/errors v0.9.1
+`,
+			modulesTxt: `# github.com/pkg/errors v0.9.0
+## explicit
+github.com/pkg/errors
+`,
+			want: []string{
+				"github.com/pkg/errors@v0.9.0 is marked as explicit in vendor/modules.txt, but is not required by go.mod",
+				"github.com/pkg/errors@v0.9.1 is required by go.mod, but vendor/modules.txt lists v0.9.0",
+			},
+		},
+		{
+			name: "explicit markers",
+			goMod: `module github.com/test/mod
+
+go 1.21
+
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.9.0
+)
+`,
+			modulesTxt: `# github.com/pkg/errors v0.9.1
+github.com/pkg/errors
+# github.com/davecgh/go-spew v1.1.1
+## explicit
+github.com/davecgh/go-spew/spew
+`,
+			want: []string{
+				"github.com/davecgh/go-spew@v1.1.1 is marked as explicit in vendor/modules.txt, but is not required by go.mod",
+				"github.com/pkg/errors@v0.9.1 is required by go.mod, but is not marked as explicit in vendor/modules.txt",
+				"github.com/stretchr/testify@v1.9.0 is required by go.mod, but is not listed in vendor/modules.txt",
+			},
+		},
+		{
+			name: "replacements",
+			goMod: `module github.com/test/mod
+
+go 1.21
+
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/other/mod v1.0.0
+)
+
+replace github.com/pkg/errors => github.com/fork/errors v0.9.2
+
+replace github.com/unused/mod v1.0.0 => ../unused
+`,
+			modulesTxt: `# github.com/pkg/errors v0.9.1 => github.com/fork/errors v0.9.3
+## explicit
+github.com/pkg/errors
+# github.com/other/mod v1.0.0 => ../other
+## explicit
+github.com/other/mod
+`,
+			want: []string{
+				"github.com/other/mod@v1.0.0 is marked as replaced in vendor/modules.txt, but is not replaced in go.mod",
+				"github.com/pkg/errors@v0.9.1 is replaced by github.com/fork/errors@v0.9.2 in go.mod, but by github.com/fork/errors@v0.9.3 in vendor/modules.txt",
+				"github.com/unused/mod@v1.0.0 is replaced in go.mod, but the replacement is not recorded in vendor/modules.txt",
+			},
+		},
+		{
+			name: "go version newer than go.mod",
+			goMod: `module github.com/test/mod
+
+go 1.21.0
+
+require github.com/pkg/errors v0.9.1
+`,
+			modulesTxt: `# github.com/pkg/errors v0.9.1
+## explicit; go 1.22
+github.com/pkg/errors
+`,
+			want: []string{
+				"github.com/pkg/errors@v0.9.1 requires go 1.22 according to vendor/modules.txt, but go.mod declares go 1.21.0",
+			},
+		},
+		{
+			name: "release candidate is older than release",
+			goMod: `module github.com/test/mod
+
+go 1.22.0
+
+require github.com/pkg/errors v0.9.1
+`,
+			modulesTxt: `# github.com/pkg/errors v0.9.1
+## explicit; go 1.22rc1
+github.com/pkg/errors
+`,
+		},
+		{
+			name: "markers are not recorded before go 1.14",
+			goMod: `module github.com/test/mod
+
+go 1.13
+
+require github.com/pkg/errors v0.9.1
+`,
+			modulesTxt: `# github.com/pkg/errors v0.9.1
+github.com/pkg/errors
+`,
+		},
+	} {
+		modFile, err := modfile.Parse("go.mod", []byte(tc.goMod), nil)
+		require.NoError(t, err, "Case %d: %s", i, tc.name)
+		got := modulesTxtInconsistencies(modFile, parseModulesTxt([]byte(tc.modulesTxt)))
+		assert.Equal(t, tc.want, got, "Case %d: %s", i, tc.name)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/palantir/godel/v2/pkg/dirchecksum"
+)
+
+// ModuleDriftType describes how the vendored copy of a module differs from the one produced by vendoring.
+type ModuleDriftType string
+
+const (
+	// ModuleAdded indicates that vendoring would add the module.
+	ModuleAdded ModuleDriftType = "added"
+	// ModuleRemoved indicates that vendoring would remove the module.
+	ModuleRemoved ModuleDriftType = "removed"
+	// ModuleVersionChanged indicates that vendoring would change the version (or replacement) of the module.
+	ModuleVersionChanged ModuleDriftType = "version changed"
+	// ModuleFilesModified indicates that the vendored files of the module differ from the ones produced by vendoring
+	// even though its version is unchanged, which typically means that the files were edited by hand.
+	ModuleFilesModified ModuleDriftType = "files modified without a version change"
+)
+
+// ModuleDrift describes the difference between the vendored copy of a module and the one produced by vendoring.
+type ModuleDrift struct {
+	// Path is the module path.
+	Path string `json:"path"`
+	// Type is the type of the difference.
+	Type ModuleDriftType `json:"type"`
+	// Before and After are the vendored versions (including replacements) of the module before and after vendoring.
+	// Before is empty for added modules and After is empty for removed modules.
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+	// Files are the paths of the files of the module that differ, relative to the vendor directory.
+	Files []string `json:"files,omitempty"`
+}
+
+// String returns a one-line description of the drift.
+func (d ModuleDrift) String() string {
+	switch d.Type {
+	case ModuleAdded:
+		return fmt.Sprintf("%s: added %s", d.Path, d.After)
+	case ModuleRemoved:
+		return fmt.Sprintf("%s: removed %s", d.Path, d.Before)
+	case ModuleVersionChanged:
+		return fmt.Sprintf("%s: version changed from %s to %s", d.Path, d.Before, d.After)
+	default:
+		return fmt.Sprintf("%s: %d %s modified without a version change (%s)", d.Path, len(d.Files), plural(len(d.Files), "file", "files"), d.Before)
+	}
+}
+
+// vendorDrift groups the provided checksum differences of a vendor directory (keyed by paths relative to the vendor
+// directory) by the module that provides each file based on the vendor/modules.txt files before and after vendoring.
+// Returns the drift for every module that differs, sorted by module path, and the paths of the differing files that
+// are not provided by any module (such as modules.txt itself).
+func vendorDrift(modulesBefore, modulesAfter []vendoredModule, checksumDiffs map[string]string) ([]ModuleDrift, []string) {
+	before := vendoredModulesByPath(modulesBefore)
+	after := vendoredModulesByPath(modulesAfter)
+
+	drifts := make(map[string]*ModuleDrift)
+	for modPath, beforeMod := range before {
+		afterMod, ok := after[modPath]
+		switch {
+		case !ok:
+			drifts[modPath] = &ModuleDrift{Path: modPath, Type: ModuleRemoved, Before: beforeMod.String()}
+		case beforeMod.String() != afterMod.String():
+			drifts[modPath] = &ModuleDrift{Path: modPath, Type: ModuleVersionChanged, Before: beforeMod.String(), After: afterMod.String()}
+		}
+	}
+	for modPath, afterMod := range after {
+		if _, ok := before[modPath]; !ok {
+			drifts[modPath] = &ModuleDrift{Path: modPath, Type: ModuleAdded, After: afterMod.String()}
+		}
+	}
+
+	var otherFiles []string
+	for file := range checksumDiffs {
+		file = path.Clean(strings.ReplaceAll(file, "\\", "/"))
+		modPath := owningModule(file, before, after)
+		if modPath == "" {
+			// directories that only contain the directories of modules are created and removed along with the modules
+			if !isModuleAncestorDir(file, before, after) {
+				otherFiles = append(otherFiles, file)
+			}
+			continue
+		}
+		drift, ok := drifts[modPath]
+		if !ok {
+			drift = &ModuleDrift{Path: modPath, Type: ModuleFilesModified, Before: before[modPath].String(), After: after[modPath].String()}
+			drifts[modPath] = drift
+		}
+		drift.Files = append(drift.Files, file)
+	}
+
+	var sortedDrifts []ModuleDrift
+	for _, drift := range drifts {
+		sort.Strings(drift.Files)
+		sortedDrifts = append(sortedDrifts, *drift)
+	}
+	sort.Slice(sortedDrifts, func(i, j int) bool {
+		return sortedDrifts[i].Path < sortedDrifts[j].Path
+	})
+	sort.Strings(otherFiles)
+	return sortedDrifts, otherFiles
+}
+
+func vendoredModulesByPath(modules []vendoredModule) map[string]vendoredModule {
+	byPath := make(map[string]vendoredModule, len(modules))
+	for _, mod := range modules {
+		// entries without a version only record replacements and do not provide any vendored files
+		if mod.Version == "" {
+			continue
+		}
+		byPath[mod.Path] = mod
+	}
+	return byPath
+}
+
+// owningModule returns the path of the module that provides the vendored file or directory at the provided path
+// (relative to the vendor directory), which is the module with the longest path that is equal to or a parent of the
+// path. Returns the empty string if no module provides the file.
+func owningModule(file string, modules ...map[string]vendoredModule) string {
+	for dir := file; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		for _, mods := range modules {
+			if _, ok := mods[dir]; ok {
+				return dir
+			}
+		}
+	}
+	return ""
+}
+
+// isModuleAncestorDir returns true if the provided path (relative to the vendor directory) is a parent directory of
+// any of the provided modules.
+func isModuleAncestorDir(dir string, modules ...map[string]vendoredModule) bool {
+	for _, mods := range modules {
+		for modPath := range mods {
+			if strings.HasPrefix(modPath, dir+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// vendorDriftSummary returns the description of a modified vendor directory, which lists the modules that differ. If
+// debug is true, the raw list of differing files is included as well.
+func vendorDriftSummary(change FileChange, debug bool) string {
+	var lines []string
+	for _, drift := range change.Modules {
+		lines = append(lines, drift.String())
+	}
+	for _, file := range change.OtherFiles {
+		lines = append(lines, fmt.Sprintf("%s modified", path.Join(change.Path, file)))
+	}
+	if debug || len(lines) == 0 {
+		checksumDiff := dirchecksum.ChecksumsDiff{
+			RootDir: change.Path,
+			Diffs:   change.ChecksumDiffs,
+		}
+		lines = append(lines, checksumDiff.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
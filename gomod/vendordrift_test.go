@@ -0,0 +1,101 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVendorDrift(t *testing.T) {
+	before := parseModulesTxt([]byte(`# github.com/pkg/errors v0.9.1
+## explicit
+github.com/pkg/errors
+# github.com/removed/mod v1.0.0
+github.com/removed/mod
+# golang.org/x/mod v0.39.0
+golang.org/x/mod/modfile
+`))
+	after := parseModulesTxt([]byte(`# github.com/pkg/errors v0.9.1
+## explicit
+github.com/pkg/errors
+# github.com/added/mod v1.1.0
+github.com/added/mod/pkg
+# golang.org/x/mod v0.40.0
+golang.org/x/mod/modfile
+`))
+	drifts, otherFiles := vendorDrift(before, after, map[string]string{
+		"github.com/pkg/errors/errors.go":   "checksum changed from abc to def",
+		"github.com/removed":                "missing",
+		"github.com/removed/mod":            "missing",
+		"github.com/removed/mod/mod.go":     "missing",
+		"github.com/added":                  "extra",
+		"github.com/added/mod":              "extra",
+		"github.com/added/mod/pkg":          "extra",
+		"github.com/added/mod/pkg/pkg.go":   "extra",
+		"golang.org/x/mod/modfile/rule.go":  "checksum changed from abc to def",
+		"golang.org/x/mod/modfile/print.go": "checksum changed from abc to def",
+		"modules.txt":                       "checksum changed from abc to def",
+	})
+	assert.Equal(t, []ModuleDrift{
+		{
+			Path:  "github.com/added/mod",
+			Type:  ModuleAdded,
+			After: "v1.1.0",
+			Files: []string{"github.com/added/mod", "github.com/added/mod/pkg", "github.com/added/mod/pkg/pkg.go"},
+		},
+		{
+			Path:   "github.com/pkg/errors",
+			Type:   ModuleFilesModified,
+			Before: "v0.9.1",
+			After:  "v0.9.1",
+			Files:  []string{"github.com/pkg/errors/errors.go"},
+		},
+		{
+			Path:   "github.com/removed/mod",
+			Type:   ModuleRemoved,
+			Before: "v1.0.0",
+			Files:  []string{"github.com/removed/mod", "github.com/removed/mod/mod.go"},
+		},
+		{
+			Path:   "golang.org/x/mod",
+			Type:   ModuleVersionChanged,
+			Before: "v0.39.0",
+			After:  "v0.40.0",
+			Files:  []string{"golang.org/x/mod/modfile/print.go", "golang.org/x/mod/modfile/rule.go"},
+		},
+	}, drifts)
+	assert.Equal(t, []string{"modules.txt"}, otherFiles)
+}
+
+func TestVendorDriftSummary(t *testing.T) {
+	change := FileChange{
+		Path: "tools/vendor",
+		Type: FileModified,
+		ChecksumDiffs: map[string]string{
+			"github.com/pkg/errors/errors.go": "checksum changed from abc to def",
+			"github.com/pkg/errors/stack.go":  "missing",
+			"modules.txt":                     "checksum changed from abc to def",
+		},
+		Modules: []ModuleDrift{
+			{
+				Path:   "github.com/pkg/errors",
+				Type:   ModuleFilesModified,
+				Before: "v0.9.1",
+				After:  "v0.9.1",
+				Files:  []string{"github.com/pkg/errors/errors.go", "github.com/pkg/errors/stack.go"},
+			},
+		},
+		OtherFiles: []string{"modules.txt"},
+	}
+	assert.Equal(t, `github.com/pkg/errors: 2 files modified without a version change (v0.9.1)
+tools/vendor/modules.txt modified`, vendorDriftSummary(change, false))
+	assert.Equal(t, `github.com/pkg/errors: 2 files modified without a version change (v0.9.1)
+tools/vendor/modules.txt modified
+tools/vendor/github.com/pkg/errors/errors.go: checksum changed from abc to def
+tools/vendor/github.com/pkg/errors/stack.go: missing
+tools/vendor/modules.txt: checksum changed from abc to def`, vendorDriftSummary(change, true))
+}
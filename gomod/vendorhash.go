@@ -0,0 +1,184 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	modmodule "golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// addVendorHashCheck verifies the files in the vendor directory of the module against the upstream copies of the
+// provided vendored modules in the module cache and records the result in report. The modules that could not be
+// verified are written to stdout.
+func addVendorHashCheck(ctx context.Context, executor Executor, mod module, goSum []byte, vendored []vendoredModule, stdout io.Writer, report *ModuleReport) error {
+	env, err := goEnv(ctx, executor, mod.Dir, "GOMODCACHE")
+	if err != nil {
+		return err
+	}
+	modCacheDir := env["GOMODCACHE"]
+	if modCacheDir == "" {
+		report.skipCheck(checkVendorHashes, "GOMODCACHE is not set")
+		return nil
+	}
+	result, err := verifyVendorHashes(filepath.Join(mod.Dir, "vendor"), modCacheDir, goSum, vendored)
+	if err != nil {
+		return err
+	}
+	for _, unverified := range result.Unverified {
+		_, _ = fmt.Fprintf(stdout, "could not verify vendored files of %s\n", unverified)
+	}
+	var failure string
+	if len(result.Mismatches) > 0 {
+		failure = fmt.Sprintf("%s contains files that differ from the upstream modules recorded in go.sum:\n%s", mod.relPath("vendor"), strings.Join(result.Mismatches, "\n"))
+	}
+	report.addCheck(checkVendorHashes, failure)
+	return nil
+}
+
+// vendorHashResult is the result of verifying the files of a vendor directory against the upstream modules.
+type vendorHashResult struct {
+	// Mismatches describe the vendored files that differ from the upstream module that provides them, sorted by module.
+	Mismatches []string
+	// Unverified describe the modules that could not be verified and the reason why.
+	Unverified []string
+}
+
+// verifyVendorHashes compares the files in vendorDir with the upstream copies of the vendored modules without
+// accessing the network. The upstream copy of a module is the extracted module in modCacheDir (GOMODCACHE), which is
+// only trusted if its content hash matches the "h1:" entry for the module in goSum. Modules that are replaced by a
+// directory on the local file system are not verified.
+func verifyVendorHashes(vendorDir, modCacheDir string, goSum []byte, vendored []vendoredModule) (vendorHashResult, error) {
+	hashes := goSumHashes(goSum)
+	vendoredPaths := make(map[string]bool, len(vendored))
+	for _, mod := range vendored {
+		if mod.Version != "" {
+			vendoredPaths[mod.Path] = true
+		}
+	}
+
+	var result vendorHashResult
+	for _, mod := range vendored {
+		if mod.Version == "" || (mod.ReplacePath != "" && mod.ReplaceVersion == "") {
+			continue
+		}
+		upstream := modmodule.Version{Path: mod.Path, Version: mod.Version}
+		if mod.ReplacePath != "" {
+			upstream = modmodule.Version{Path: mod.ReplacePath, Version: mod.ReplaceVersion}
+		}
+		hash, ok := hashes[upstream]
+		if !ok {
+			result.Unverified = append(result.Unverified, fmt.Sprintf("%s: go.sum does not contain its hash", upstream))
+			continue
+		}
+		moduleDir, err := moduleCacheDir(modCacheDir, upstream)
+		if err != nil {
+			return vendorHashResult{}, err
+		}
+		if !dirExists(moduleDir) {
+			result.Unverified = append(result.Unverified, fmt.Sprintf("%s: not in the module cache", upstream))
+			continue
+		}
+		upstreamHash, err := dirhash.HashDir(moduleDir, upstream.String(), dirhash.Hash1)
+		if err != nil {
+			return vendorHashResult{}, errors.Wrapf(err, "failed to compute hash of %s", moduleDir)
+		}
+		if upstreamHash != hash {
+			result.Unverified = append(result.Unverified, fmt.Sprintf("%s: the module cache copy does not match go.sum", upstream))
+			continue
+		}
+		files, err := vendoredModuleFiles(vendorDir, mod.Path, vendoredPaths)
+		if err != nil {
+			return vendorHashResult{}, err
+		}
+		for _, file := range files {
+			vendoredContent, err := os.ReadFile(filepath.Join(vendorDir, filepath.FromSlash(path.Join(mod.Path, file))))
+			if err != nil {
+				return vendorHashResult{}, errors.Wrapf(err, "failed to read vendored file")
+			}
+			upstreamContent, err := os.ReadFile(filepath.Join(moduleDir, filepath.FromSlash(file)))
+			switch {
+			case os.IsNotExist(err):
+				result.Mismatches = append(result.Mismatches, fmt.Sprintf("%s: %s is not part of the upstream module", upstream, path.Join(mod.Path, file)))
+			case err != nil:
+				return vendorHashResult{}, errors.Wrapf(err, "failed to read file in module cache")
+			case !bytes.Equal(vendoredContent, upstreamContent):
+				result.Mismatches = append(result.Mismatches, fmt.Sprintf("%s: %s differs from the upstream module", upstream, path.Join(mod.Path, file)))
+			}
+		}
+	}
+	return result, nil
+}
+
+// goSumHashes returns the "h1:" hashes of the module content (not of the go.mod files) recorded in the provided go.sum
+// content.
+func goSumHashes(goSum []byte) map[modmodule.Version]string {
+	hashes := make(map[modmodule.Version]string)
+	for _, line := range strings.Split(string(goSum), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") || !strings.HasPrefix(fields[2], "h1:") {
+			continue
+		}
+		hashes[modmodule.Version{Path: fields[0], Version: fields[1]}] = fields[2]
+	}
+	return hashes
+}
+
+// moduleCacheDir returns the directory in the module cache into which the provided module is extracted.
+func moduleCacheDir(modCacheDir string, mod modmodule.Version) (string, error) {
+	escapedPath, err := modmodule.EscapePath(mod.Path)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid module path %s", mod.Path)
+	}
+	escapedVersion, err := modmodule.EscapeVersion(mod.Version)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid version %s of module %s", mod.Version, mod.Path)
+	}
+	return filepath.Join(modCacheDir, filepath.FromSlash(escapedPath+"@"+escapedVersion)), nil
+}
+
+// vendoredModuleFiles returns the paths (relative to the directory of the module) of the vendored files of the module
+// with the provided path. The directories of the other vendored modules whose paths are nested in the module path are
+// skipped.
+func vendoredModuleFiles(vendorDir, modPath string, vendoredPaths map[string]bool) ([]string, error) {
+	moduleDir := filepath.Join(vendorDir, filepath.FromSlash(modPath))
+	if !dirExists(moduleDir) {
+		return nil, nil
+	}
+	var files []string
+	if err := filepath.WalkDir(moduleDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(moduleDir, filePath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if d.IsDir() {
+			if relPath != "." && vendoredPaths[path.Join(modPath, relPath)] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		files = append(files, relPath)
+		return nil
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list vendored files of %s", modPath)
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+func TestVerifyVendorHashes(t *testing.T) {
+	modCacheDir := t.TempDir()
+	writeTestFiles(modCacheDir, map[string]string{
+		"github.com/pkg/errors@v0.9.1/errors.go":        "package errors\n",
+		"github.com/pkg/errors@v0.9.1/stack.go":         "package errors\n\n// stack\n",
+		"github.com/pkg/errors@v0.9.1/LICENSE":          "license\n",
+		"github.com/!burnt!sushi/toml@v1.0.0/decode.go": "package toml\n",
+		"github.com/corrupt/mod@v1.0.0/mod.go":          "package mod\n",
+	})
+	errorsHash, err := dirhash.HashDir(filepath.Join(modCacheDir, "github.com/pkg/errors@v0.9.1"), "github.com/pkg/errors@v0.9.1", dirhash.Hash1)
+	require.NoError(t, err)
+	tomlHash, err := dirhash.HashDir(filepath.Join(modCacheDir, "github.com/!burnt!sushi/toml@v1.0.0"), "github.com/BurntSushi/toml@v1.0.0", dirhash.Hash1)
+	require.NoError(t, err)
+	goSum := fmt.Sprintf(`github.com/BurntSushi/toml v1.0.0 %s
+github.com/BurntSushi/toml v1.0.0/go.mod h1:go.mod=
+github.com/corrupt/mod v1.0.0 h1:corrupt=
+github.com/pkg/errors v0.9.1 %s
+github.com/pkg/errors v0.9.1/go.mod h1:go.mod=
+`, tomlHash, errorsHash)
+
+	vendorDir := t.TempDir()
+	writeTestFiles(vendorDir, map[string]string{
+		"github.com/pkg/errors/errors.go":      "package errors\n",
+		"github.com/pkg/errors/stack.go":       "package errors\n\n// patched by hand\n",
+		"github.com/pkg/errors/LICENSE":        "license\n",
+		"github.com/pkg/errors/extra.go":       "package errors\n",
+		"github.com/pkg/errors/nested/mod.go":  "package nested\n",
+		"github.com/BurntSushi/toml/decode.go": "package toml\n",
+		"github.com/corrupt/mod/mod.go":        "package mod\n",
+		"github.com/missing/mod/mod.go":        "package mod\n",
+		"github.com/uncached/mod/mod.go":       "package mod\n",
+		"github.com/local/mod/mod.go":          "package local\n",
+	})
+	vendored := parseModulesTxt([]byte(`# github.com/BurntSushi/toml v1.0.0
+## explicit
+github.com/BurntSushi/toml
+# github.com/corrupt/mod v1.0.0
+## explicit
+github.com/corrupt/mod
+# github.com/local/mod v1.0.0 => ../local
+## explicit
+github.com/local/mod
+# github.com/missing/mod v1.0.0
+## explicit
+github.com/missing/mod
+# github.com/pkg/errors v0.9.1
+## explicit
+github.com/pkg/errors
+# github.com/pkg/errors/nested v1.0.0
+github.com/pkg/errors/nested
+# github.com/uncached/mod v1.0.0 => github.com/fork/mod v1.0.1
+## explicit
+github.com/uncached/mod
+`))
+	goSum += "github.com/fork/mod v1.0.1 h1:fork=\n"
+
+	result, err := verifyVendorHashes(vendorDir, modCacheDir, []byte(goSum), vendored)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"github.com/pkg/errors@v0.9.1: github.com/pkg/errors/extra.go is not part of the upstream module",
+		"github.com/pkg/errors@v0.9.1: github.com/pkg/errors/stack.go differs from the upstream module",
+	}, result.Mismatches)
+	assert.Equal(t, []string{
+		"github.com/corrupt/mod@v1.0.0: the module cache copy does not match go.sum",
+		"github.com/missing/mod@v1.0.0: go.sum does not contain its hash",
+		"github.com/pkg/errors/nested@v1.0.0: go.sum does not contain its hash",
+		"github.com/fork/mod@v1.0.1: not in the module cache",
+	}, result.Unverified)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+)
+
+// VendorDecision records whether "go mod vendor" should be run for a module and the rule that determined it.
+type VendorDecision struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason"`
+}
+
+func (d VendorDecision) String() string {
+	state := "disabled"
+	if d.Enabled {
+		state = "enabled"
+	}
+	return fmt.Sprintf("vendoring %s: %s", state, d.Reason)
+}
+
+// resolveVendorMode determines whether vendoring is enabled for the module in moduleDir. If mode is VendorModeAlways
+// or VendorModeNever, the mode determines the result. Otherwise, the same rules as the go command are used: if the
+// effective value of GOFLAGS (as reported by "go env") contains a "-mod" flag, vendoring is enabled if its value is
+// "vendor". If it does not, vendoring is enabled if vendor/modules.txt exists and the go directive in go.mod is 1.14
+// or later.
+func resolveVendorMode(ctx context.Context, executor Executor, moduleDir string, mode VendorMode) (VendorDecision, error) {
+	return resolveVendorModeForFile(ctx, executor, moduleDir, "go.mod", "1.14", mode)
+}
+
+// resolveWorkspaceVendorMode determines whether vendoring is enabled for the provided workspace. The rules are the
+// same as those of resolveVendorMode, except that the go directive of go.work must be 1.22 or later.
+func resolveWorkspaceVendorMode(ctx context.Context, executor Executor, ws *workspace, mode VendorMode) (VendorDecision, error) {
+	return resolveVendorModeForFile(ctx, executor, ws.Dir, "go.work", "1.22", mode)
+}
+
+func resolveVendorModeForFile(ctx context.Context, executor Executor, dir, fileName, minGoVersion string, mode VendorMode) (VendorDecision, error) {
+	switch mode {
+	case VendorModeAlways:
+		return VendorDecision{Enabled: true, Reason: fmt.Sprintf("vendor-mode is %q", mode)}, nil
+	case VendorModeNever:
+		return VendorDecision{Enabled: false, Reason: fmt.Sprintf("vendor-mode is %q", mode)}, nil
+	}
+
+	env, err := goEnv(ctx, executor, dir, "GOFLAGS")
+	if err != nil {
+		return VendorDecision{}, err
+	}
+	if modFlagVal, ok := modFlag(env["GOFLAGS"]); ok {
+		return VendorDecision{
+			Enabled: modFlagVal == "vendor",
+			Reason:  fmt.Sprintf("GOFLAGS contains -mod=%s", modFlagVal),
+		}, nil
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "vendor", "modules.txt")); err != nil {
+		return VendorDecision{Enabled: false, Reason: "GOFLAGS does not specify -mod and vendor/modules.txt does not exist"}, nil
+	}
+	goVersion, err := goDirective(filepath.Join(dir, fileName))
+	if err != nil {
+		return VendorDecision{}, err
+	}
+	// only the language version is compared, so a release candidate such as "1.22rc1" counts as "1.22"
+	if goVersion == "" || version.Compare(version.Lang("go"+goVersion), "go"+minGoVersion) < 0 {
+		return VendorDecision{Enabled: false, Reason: fmt.Sprintf("vendor/modules.txt exists but the go directive in %s is earlier than %s", fileName, minGoVersion)}, nil
+	}
+	return VendorDecision{Enabled: true, Reason: fmt.Sprintf("vendor/modules.txt exists and the go directive in %s is %s", fileName, goVersion)}, nil
+}
+
+// modFlag returns the value of the last "-mod" flag in the provided GOFLAGS value. Returns false if GOFLAGS does not
+// contain a "-mod" flag.
+func modFlag(goFlags string) (string, bool) {
+	var val string
+	var found bool
+	for _, flag := range strings.Fields(goFlags) {
+		flag = strings.TrimPrefix(strings.TrimPrefix(flag, "-"), "-")
+		if v, ok := strings.CutPrefix(flag, "mod="); ok {
+			val, found = v, true
+		}
+	}
+	return val, found
+}
+
+// goEnv returns the values of the provided keys as reported by "go env -json" when run in dir.
+func goEnv(ctx context.Context, executor Executor, dir string, keys ...string) (map[string]string, error) {
+	args := append([]string{"env", "-json"}, keys...)
+	output := &bytes.Buffer{}
+	if _, err := execute(ctx, executor, dir, nil, output, args...); err != nil {
+		return nil, err
+	}
+	env := make(map[string]string)
+	if err := json.Unmarshal(output.Bytes(), &env); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal output of go %v as JSON", args)
+	}
+	return env, nil
+}
+
+// goDirective returns the version specified by the go directive in the provided go.mod or go.work file. Returns the
+// empty string if the file does not contain a go directive.
+func goDirective(filePath string) (string, error) {
+	directives, err := readGoDirectives(filePath)
+	if err != nil {
+		return "", err
+	}
+	return directives.Go, nil
+}
+
+// goDirectives are the values of the go and toolchain directives of a go.mod or go.work file. A value is empty if the
+// file does not contain the corresponding directive.
+type goDirectives struct {
+	Go        string
+	Toolchain string
+}
+
+// readGoDirectives returns the values of the go and toolchain directives in the provided go.mod or go.work file.
+func readGoDirectives(filePath string) (goDirectives, error) {
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return goDirectives{}, errors.Wrapf(err, "failed to read %s", filePath)
+	}
+	var goStmt *modfile.Go
+	var toolchainStmt *modfile.Toolchain
+	if filepath.Base(filePath) == "go.work" {
+		workFile, err := modfile.ParseWork(filePath, fileBytes, nil)
+		if err != nil {
+			return goDirectives{}, errors.Wrapf(err, "failed to parse %s", filePath)
+		}
+		goStmt, toolchainStmt = workFile.Go, workFile.Toolchain
+	} else {
+		modFile, err := modfile.Parse(filePath, fileBytes, nil)
+		if err != nil {
+			return goDirectives{}, errors.Wrapf(err, "failed to parse %s", filePath)
+		}
+		goStmt, toolchainStmt = modFile.Go, modFile.Toolchain
+	}
+	var directives goDirectives
+	if goStmt != nil {
+		directives.Go = goStmt.Version
+	}
+	if toolchainStmt != nil {
+		directives.Toolchain = toolchainStmt.Name
+	}
+	return directives, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModFlag(t *testing.T) {
+	for i, tc := range []struct {
+		goFlags string
+		want    string
+		wantOK  bool
+	}{
+		{"", "", false},
+		{"-v -x", "", false},
+		{"-mod=vendor", "vendor", true},
+		{"--mod=mod", "mod", true},
+		{"-mod=vendor -trimpath -mod=readonly", "readonly", true},
+	} {
+		got, ok := modFlag(tc.goFlags)
+		assert.Equal(t, tc.want, got, "Case %d", i)
+		assert.Equal(t, tc.wantOK, ok, "Case %d", i)
+	}
+}
+
+func TestResolveVendorModeGoDirective(t *testing.T) {
+	for i, tc := range []struct {
+		goVersion string
+		want      bool
+	}{
+		{"1.14", true},
+		{"1.13", false},
+		{"1.21.3", true},
+		{"1.14rc1", true},
+		{"1.9", false},
+	} {
+		moduleDir := t.TempDir()
+		writeTestFiles(moduleDir, map[string]string{
+			"go.mod":             "module github.com/mod/test\n\ngo " + tc.goVersion + "\n",
+			"vendor/modules.txt": testModulesTxt,
+		})
+		decision, err := resolveVendorMode(context.Background(), &fakeToolchain{}, moduleDir, VendorModeAuto)
+		require.NoError(t, err, "Case %d: %s", i, tc.goVersion)
+		assert.Equal(t, tc.want, decision.Enabled, "Case %d: %s: %s", i, tc.goVersion, decision.Reason)
+	}
+}
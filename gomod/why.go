@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WhyReport explains why modules are part of the build list of every module in a project.
+type WhyReport struct {
+	// ProjectDir is the absolute path to the project directory.
+	ProjectDir string `json:"projectDir"`
+	// Modules are the results for the modules in the project.
+	Modules []WhyModuleReport `json:"modules"`
+}
+
+// WhyModuleReport explains why modules are part of the build list of a single module.
+type WhyModuleReport struct {
+	// Path is the module path.
+	Path string `json:"path"`
+	// Dir is the directory of the module relative to the project directory.
+	Dir string `json:"dir"`
+	// Dependencies are the results for the requested modules in the order in which they were requested.
+	Dependencies []WhyDependency `json:"dependencies"`
+}
+
+// WhyDependency explains why a module is part of the build list of a module.
+type WhyDependency struct {
+	// Path is the module path.
+	Path string `json:"path"`
+	// ImportChain is the shortest chain of imports from a package in the main module to a package in the module as
+	// reported by "go mod why -m". It is empty if no package in the main module needs the module.
+	ImportChain []string `json:"importChain,omitempty"`
+	// RequirementChain is the shortest chain of requirements from the main module to a version of the module in the
+	// requirement graph reported by "go mod graph" (for example, ["github.com/mod/test", "github.com/pkg/errors@v0.9.1"]).
+	// It is empty if the module is not in the requirement graph.
+	RequirementChain []string `json:"requirementChain,omitempty"`
+}
+
+// Why returns the shortest import chain and the shortest requirement chain to every one of the provided modules for
+// every module in projectDir.
+func Why(ctx context.Context, projectDir string, modPaths []string, params Params) (*WhyReport, error) {
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine absolute path of project directory")
+	}
+	modules, err := discoverModules(projectDir, params.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	if env := toolchainEnv(false, params.Toolchain); len(env) > 0 {
+		params.Executor = envExecutor{Executor: params.executor(), env: env}
+	}
+
+	report := &WhyReport{
+		ProjectDir: projectDir,
+		Modules:    make([]WhyModuleReport, len(modules)),
+	}
+	moduleErrs := make([]error, len(modules))
+	forEachParallel(len(modules), params.Concurrency, func(i int) {
+		report.Modules[i] = WhyModuleReport{
+			Path: modules[i].Path,
+			Dir:  modules[i].RelDir,
+		}
+		report.Modules[i].Dependencies, moduleErrs[i] = whyModule(ctx, params.executor(), modules[i], modPaths)
+	})
+	var errs []error
+	for i, mod := range modules {
+		if moduleErrs[i] != nil {
+			errs = append(errs, &ModuleError{Module: mod.Path, Err: moduleErrs[i]})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, joinErrors(errs)
+	}
+	return report, nil
+}
+
+// whyModule returns the import and requirement chains to the provided modules for the provided module.
+func whyModule(ctx context.Context, executor Executor, mod module, modPaths []string) ([]WhyDependency, error) {
+	chains, err := importChains(ctx, executor, mod.Dir, filepath.Join(mod.Dir, "go.mod"), modPaths)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := readRequirementGraph(ctx, executor, mod.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var dependencies []WhyDependency
+	for _, modPath := range modPaths {
+		dependencies = append(dependencies, WhyDependency{
+			Path:             modPath,
+			ImportChain:      chains[modPath],
+			RequirementChain: graph.shortestChain(modPath),
+		})
+	}
+	return dependencies, nil
+}
+
+// WriteJSON writes the report to w as indented JSON.
+func (r *WhyReport) WriteJSON(w io.Writer) error {
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal report as JSON")
+	}
+	if _, err := fmt.Fprintln(w, string(out)); err != nil {
+		return errors.Wrapf(err, "failed to write report")
+	}
+	return nil
+}
+
+// WriteText writes the chains of every requested module for every module in the project to w.
+func (r *WhyReport) WriteText(w io.Writer) error {
+	buf := &strings.Builder{}
+	for i, mod := range r.Modules {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		_, _ = fmt.Fprintf(buf, "%s:\n", mod.Path)
+		for _, dep := range mod.Dependencies {
+			_, _ = fmt.Fprintf(buf, "# %s\n", dep.Path)
+			if len(dep.ImportChain) == 0 {
+				_, _ = fmt.Fprintf(buf, "import chain: no package in %s needs the module\n", mod.Path)
+			} else {
+				_, _ = fmt.Fprintf(buf, "import chain:\n    %s\n", strings.Join(dep.ImportChain, "\n    "))
+			}
+			if len(dep.RequirementChain) == 0 {
+				buf.WriteString("requirement chain: the module is not in the requirement graph\n")
+			} else {
+				_, _ = fmt.Fprintf(buf, "requirement chain: %s\n", strings.Join(dep.RequirementChain, " -> "))
+			}
+		}
+	}
+	if _, err := io.WriteString(w, buf.String()); err != nil {
+		return errors.Wrapf(err, "failed to write report")
+	}
+	return nil
+}
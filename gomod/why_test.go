@@ -0,0 +1,54 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhy(t *testing.T) {
+	projectDir := newTestModule(t, true)
+	toolchain := newUpToDateToolchain()
+	toolchain.whyOutput = testModWhy + "\n# github.com/foo/qux\n(main module does not need module github.com/foo/qux)\n"
+	toolchain.graphOutput = testModGraph
+
+	report, err := Why(context.Background(), projectDir, []string{"github.com/pkg/errors", "github.com/foo/qux"}, Params{Executor: toolchain})
+	require.NoError(t, err)
+	assert.Equal(t, []WhyModuleReport{
+		{
+			Path: "github.com/mod/test",
+			Dir:  ".",
+			Dependencies: []WhyDependency{
+				{
+					Path:             "github.com/pkg/errors",
+					ImportChain:      []string{"github.com/mod/test", "github.com/pkg/errors"},
+					RequirementChain: []string{"github.com/mod/test", "github.com/pkg/errors@v0.9.1"},
+				},
+				{
+					Path:             "github.com/foo/qux",
+					RequirementChain: []string{"github.com/mod/test", "github.com/foo/bar@v1.0.0", "github.com/foo/baz@v1.1.0", "github.com/foo/qux@v0.1.0"},
+				},
+			},
+		},
+	}, report.Modules)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.WriteText(buf))
+	assert.Equal(t, `github.com/mod/test:
+# github.com/pkg/errors
+import chain:
+    github.com/mod/test
+    github.com/pkg/errors
+requirement chain: github.com/mod/test -> github.com/pkg/errors@v0.9.1
+# github.com/foo/qux
+import chain: no package in github.com/mod/test needs the module
+requirement chain: github.com/mod/test -> github.com/foo/bar@v1.0.0 -> github.com/foo/baz@v1.1.0 -> github.com/foo/qux@v0.1.0
+`, buf.String())
+}
@@ -0,0 +1,484 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+)
+
+// workspace is a go.work file at the root of a project.
+type workspace struct {
+	// Dir is the directory that contains the go.work file.
+	Dir string
+	// UseDirs are the absolute paths of the module directories listed in the use directives of the go.work file.
+	UseDirs []string
+}
+
+// readWorkspace returns the workspace defined by the go.work file in projectDir. Returns nil if the file does not
+// exist.
+func readWorkspace(projectDir string) (*workspace, error) {
+	goWorkPath := filepath.Join(projectDir, "go.work")
+	goWorkBytes, err := os.ReadFile(goWorkPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", goWorkPath)
+	}
+	workFile, err := modfile.ParseWork(goWorkPath, goWorkBytes, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", goWorkPath)
+	}
+	ws := &workspace{
+		Dir: projectDir,
+	}
+	for _, use := range workFile.Use {
+		useDir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(useDir) {
+			useDir = filepath.Join(projectDir, useDir)
+		}
+		ws.UseDirs = append(ws.UseDirs, filepath.Clean(useDir))
+	}
+	return ws, nil
+}
+
+// contains returns true if the provided module is listed in the use directives of the workspace. Returns false if the
+// receiver is nil.
+func (w *workspace) contains(mod module) bool {
+	if w == nil {
+		return false
+	}
+	return slices.Contains(w.UseDirs, filepath.Clean(mod.Dir))
+}
+
+// workspaceFiles returns the paths of the files that may be modified by "go work sync": the go.work and go.work.sum
+// files and the go.mod and go.sum files of every module in the workspace.
+func (w *workspace) workspaceFiles() []string {
+	files := []string{
+		filepath.Join(w.Dir, "go.work"),
+		filepath.Join(w.Dir, "go.work.sum"),
+	}
+	for _, useDir := range w.UseDirs {
+		files = append(files, filepath.Join(useDir, "go.mod"), filepath.Join(useDir, "go.sum"))
+	}
+	return files
+}
+
+// runWorkspace runs "go work sync" followed by "go work vendor" (if vendoring is enabled for the workspace), recording
+// the vendor decision, changes and verification failures in report. The operations are run against a copy of the
+// workspace (see newWorkspaceCopy) in which moduleFiles (the pending updates of the go.mod and go.sum files of the
+// modules) have been applied, so the project is never modified by this function. If verify is true, every file that
+// would be modified by the operations is recorded as a failure. Otherwise, the files modified by "go work sync" and the
+// generated vendor directory are recorded in update, which is written to the project by runProject.
+func runWorkspace(ctx context.Context, ws *workspace, verify bool, params Params, stdout io.Writer, report *ModuleReport, moduleFiles []fileChange, update *pendingUpdate) (rErr error) {
+	if verify && params.Workspace.ForbidGoWork {
+		committed, err := goWorkCommitted(ctx, ws.Dir)
+		if err != nil {
+			return err
+		}
+		var failure string
+		if committed {
+			failure = "go.work is committed to the repository, but workspace.forbid-go-work is true"
+		}
+		report.addCheck(checkForbidGoWork, failure)
+	}
+
+	vendor, err := resolveWorkspaceVendorMode(ctx, params.executor(), ws, params.VendorMode)
+	if err != nil {
+		return err
+	}
+	report.Vendor = vendor
+	_, _ = fmt.Fprintln(stdout, vendor.String())
+
+	wsCopy, err := newWorkspaceCopy(ws, moduleFiles)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := wsCopy.remove(); err != nil && rErr == nil {
+			rErr = err
+		}
+	}()
+	if err := run(ctx, params.executor(), wsCopy.path(ws.Dir), wsCopy.env(ws), stdout, "work", "sync"); err != nil {
+		return err
+	}
+	synced, err := wsCopy.changed(ws, moduleFiles)
+	if err != nil {
+		return err
+	}
+	changes, err := ws.fileChanges(synced)
+	if err != nil {
+		return err
+	}
+	report.Changes = append(report.Changes, changes...)
+	if !verify {
+		return applyWorkspace(ctx, ws, wsCopy, synced, vendor, params, stdout, report, update)
+	}
+
+	var syncFailures []string
+	for _, change := range changes {
+		syncFailures = append(syncFailures, fileFailure(change, "modified by go work sync"))
+	}
+	report.addCheck(checkGoWorkSync, strings.Join(syncFailures, "\n"))
+	switch {
+	case len(syncFailures) > 0:
+		report.skipCheck(checkVendor, "go work sync would modify files")
+		return nil
+	case !vendor.Enabled:
+		report.skipCheck(checkVendor, vendor.String())
+		return nil
+	case params.Verify.SkipVendor:
+		report.skipCheck(checkVendor, "verify.skip-vendor is true")
+		return nil
+	}
+
+	scratchDir, err := os.MkdirTemp("", "godel-mod-plugin-verify-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary directory")
+	}
+	defer func() {
+		if err := os.RemoveAll(scratchDir); err != nil && rErr == nil {
+			rErr = errors.Wrapf(err, "failed to remove temporary directory %s", scratchDir)
+		}
+	}()
+	scratchVendorDirPath := filepath.Join(scratchDir, "vendor")
+	if err := run(ctx, params.executor(), wsCopy.path(ws.Dir), wsCopy.env(ws), stdout, "work", "vendor", "-o", scratchVendorDirPath); err != nil {
+		return err
+	}
+	change, err := vendorDirChange("vendor", filepath.Join(ws.Dir, "vendor"), scratchVendorDirPath)
+	if err != nil {
+		return err
+	}
+	if change == nil {
+		report.addCheck(checkVendor, "")
+		return nil
+	}
+	report.Changes = append(report.Changes, *change)
+	report.addCheck(checkVendor, vendorFailure(*change, params.Debug))
+	return nil
+}
+
+// applyWorkspace runs "go work vendor" (if vendoring is enabled) against the copy of the workspace on which
+// "go work sync" was run and records the change in report. The vendor directory is generated in a temporary directory
+// next to the existing one so that it can be moved into place with a rename. The files modified by "go work sync" and
+// the generated vendor directory are recorded in update.
+func applyWorkspace(ctx context.Context, ws *workspace, wsCopy *workspaceCopy, synced []fileChange, vendor VendorDecision, params Params, stdout io.Writer, report *ModuleReport, update *pendingUpdate) error {
+	update.files = synced
+	if !vendor.Enabled {
+		return nil
+	}
+	newVendorDirPath, err := os.MkdirTemp(ws.Dir, ".vendor-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary directory")
+	}
+	update.newVendorDirPath, update.vendorDirPath = newVendorDirPath, filepath.Join(ws.Dir, "vendor")
+	if err := run(ctx, params.executor(), wsCopy.path(ws.Dir), wsCopy.env(ws), stdout, "work", "vendor", "-o", newVendorDirPath); err != nil {
+		return err
+	}
+	change, err := vendorDirChange("vendor", update.vendorDirPath, newVendorDirPath)
+	if err != nil {
+		return err
+	}
+	if change != nil {
+		report.Changes = append(report.Changes, *change)
+	}
+	return nil
+}
+
+// fileChanges returns the provided changes to the files of the workspace as FileChanges with paths relative to the
+// directory of the workspace.
+func (w *workspace) fileChanges(changed []fileChange) ([]FileChange, error) {
+	var changes []FileChange
+	for _, change := range changed {
+		relPath, err := filepath.Rel(w.Dir, change.path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to determine relative path of %s", change.path)
+		}
+		if c := contentChange(filepath.ToSlash(relPath), change.before, change.after); c != nil {
+			changes = append(changes, *c)
+		}
+	}
+	return changes, nil
+}
+
+// workspaceCopy is a copy of a workspace in a temporary directory. "go work sync" updates the go.mod and go.sum files
+// of every module in the workspace and loads the packages of the modules, so it is run against a copy of the go.work
+// and go.work.sum files and of the module directories to determine its changes without modifying the project.
+type workspaceCopy struct {
+	// srcRoot is the deepest directory that contains the go.work file and every copied module directory.
+	srcRoot string
+	// dir is the temporary directory that contains the copy of srcRoot.
+	dir string
+}
+
+// newWorkspaceCopy copies the go.work and go.work.sum files, the module directories of the provided workspace and the
+// directories that are the targets of relative local replace directives (see localReplaceDirs) to a temporary
+// directory, preserving their locations relative to each other so that the relative paths in the go.work and go.mod
+// files resolve to the copies. Only the files of the modules that are read by "go work sync" are copied (see
+// copyModuleDir). The content of the files of the workspace (see workspaceFiles) in the provided overrides replaces
+// their content in the project.
+func newWorkspaceCopy(ws *workspace, overrides []fileChange) (*workspaceCopy, error) {
+	replaceDirs, err := ws.localReplaceDirs(overrides)
+	if err != nil {
+		return nil, err
+	}
+	moduleDirs := append(slices.Clone(ws.UseDirs), replaceDirs...)
+	srcRoot := ws.Dir
+	for _, moduleDir := range moduleDirs {
+		for !pathWithin(moduleDir, srcRoot) {
+			parent := filepath.Dir(srcRoot)
+			if parent == srcRoot {
+				return nil, errors.Errorf("failed to determine a directory that contains %s and %s", ws.Dir, moduleDir)
+			}
+			srcRoot = parent
+		}
+	}
+	dir, err := os.MkdirTemp("", "godel-mod-plugin-work-")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create temporary directory")
+	}
+	wsCopy := &workspaceCopy{
+		srcRoot: srcRoot,
+		dir:     dir,
+	}
+	if err := wsCopy.copyFiles(ws, moduleDirs, overrides); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	return wsCopy, nil
+}
+
+// localReplaceDirs returns the absolute paths of the existing directories that are the targets of the replace
+// directives with relative filesystem paths in the go.work file and in the go.mod files of the modules of the
+// workspace, excluding the module directories of the workspace. The content of a go.mod file in the provided overrides
+// replaces its content in the project. Replace directives with absolute paths resolve to the same directory from the
+// copy, so they are ignored.
+func (w *workspace) localReplaceDirs(overrides []fileChange) ([]string, error) {
+	var dirs []string
+	addDirs := func(baseDir string, replaces []*modfile.Replace) error {
+		for _, rep := range replaces {
+			if !modfile.IsDirectoryPath(rep.New.Path) || filepath.IsAbs(filepath.FromSlash(rep.New.Path)) {
+				continue
+			}
+			dir := filepath.Join(baseDir, filepath.FromSlash(rep.New.Path))
+			if slices.Contains(w.UseDirs, dir) || slices.Contains(dirs, dir) {
+				continue
+			}
+			if _, err := os.Stat(dir); os.IsNotExist(err) {
+				// the go command reports the missing directory when it is run against the copy
+				continue
+			} else if err != nil {
+				return errors.Wrapf(err, "failed to stat %s", dir)
+			}
+			dirs = append(dirs, dir)
+		}
+		return nil
+	}
+
+	goWorkPath := filepath.Join(w.Dir, "go.work")
+	goWork, err := os.ReadFile(goWorkPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", goWorkPath)
+	}
+	workFile, err := modfile.ParseWork(goWorkPath, goWork, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", goWorkPath)
+	}
+	if err := addDirs(w.Dir, workFile.Replace); err != nil {
+		return nil, err
+	}
+	for _, useDir := range w.UseDirs {
+		goModPath := filepath.Join(useDir, "go.mod")
+		goMod, err := readFileIfExists(goModPath)
+		if err != nil {
+			return nil, err
+		}
+		if i := slices.IndexFunc(overrides, func(override fileChange) bool { return override.path == goModPath }); i != -1 {
+			goMod = overrides[i].after
+		}
+		if goMod == nil {
+			continue
+		}
+		modFile, err := modfile.Parse(goModPath, goMod, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", goModPath)
+		}
+		if err := addDirs(useDir, modFile.Replace); err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
+
+func (c *workspaceCopy) copyFiles(ws *workspace, moduleDirs []string, overrides []fileChange) error {
+	for _, moduleDir := range moduleDirs {
+		if err := copyModuleDir(moduleDir, c.path(moduleDir)); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(c.path(ws.Dir), 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory %s", c.path(ws.Dir))
+	}
+	for _, name := range []string{"go.work", "go.work.sum"} {
+		content, err := readFileIfExists(filepath.Join(ws.Dir, name))
+		if err != nil {
+			return err
+		}
+		if content == nil {
+			continue
+		}
+		if err := os.WriteFile(c.path(filepath.Join(ws.Dir, name)), content, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write %s", c.path(filepath.Join(ws.Dir, name)))
+		}
+	}
+	for _, override := range overrides {
+		if !slices.Contains(ws.workspaceFiles(), override.path) {
+			continue
+		}
+		path := c.path(override.path)
+		if override.after == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "failed to remove %s", path)
+			}
+			continue
+		}
+		if err := os.WriteFile(path, override.after, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write %s", path)
+		}
+	}
+	return nil
+}
+
+// path returns the path in the copy that corresponds to the provided path in the project.
+func (c *workspaceCopy) path(path string) string {
+	// the paths are absolute, so the relative path can always be determined
+	relPath, _ := filepath.Rel(c.srcRoot, path)
+	return filepath.Join(c.dir, relPath)
+}
+
+// env returns the environment variables that select the go.work file of the copy.
+func (c *workspaceCopy) env(ws *workspace) []string {
+	return []string{"GOWORK=" + c.path(filepath.Join(ws.Dir, "go.work"))}
+}
+
+// changed returns the files of the workspace (see workspaceFiles) whose content in the copy differs from their content
+// in the project (or in the provided overrides, which take precedence), where after is the content in the copy.
+func (c *workspaceCopy) changed(ws *workspace, overrides []fileChange) ([]fileChange, error) {
+	var changes []fileChange
+	for _, path := range ws.workspaceFiles() {
+		before, err := readFileIfExists(path)
+		if err != nil {
+			return nil, err
+		}
+		if i := slices.IndexFunc(overrides, func(override fileChange) bool { return override.path == path }); i != -1 {
+			before = overrides[i].after
+		}
+		after, err := readFileIfExists(c.path(path))
+		if err != nil {
+			return nil, err
+		}
+		// "go work sync" never removes files, so a file that is missing from the copy is unchanged
+		if after != nil && (before == nil || !bytes.Equal(before, after)) {
+			changes = append(changes, fileChange{
+				path:   path,
+				before: before,
+				after:  after,
+			})
+		}
+	}
+	return changes, nil
+}
+
+// remove removes the temporary directory that contains the copy.
+func (c *workspaceCopy) remove() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return errors.Wrapf(err, "failed to remove temporary directory %s", c.dir)
+	}
+	return nil
+}
+
+// copyModuleDir copies the files of the module in the directory src that are read by "go work sync" to dst: the go.mod
+// and go.sum files and the Go source files of its packages. The directories that are ignored by the go command (vendor
+// and testdata directories and directories whose names start with "." or "_") and the directories of nested modules
+// are skipped.
+func copyModuleDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", path)
+		}
+		if d.IsDir() {
+			if path == src {
+				return nil
+			}
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if name := d.Name(); !d.Type().IsRegular() || (name != "go.mod" && name != "go.sum" && !strings.HasSuffix(name, ".go")) {
+			return nil
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to determine relative path of %s", path)
+		}
+		target := filepath.Join(dst, relPath)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return errors.Wrapf(err, "failed to create directory %s", filepath.Dir(target))
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", path)
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write %s", target)
+		}
+		return nil
+	})
+}
+
+// pathWithin returns true if path is dir or is within dir.
+func pathWithin(path, dir string) bool {
+	relPath, err := filepath.Rel(dir, path)
+	return err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator))
+}
+
+// goWorkCommitted returns true if the go.work file in dir is tracked by git. If dir is not in a git repository, the
+// file is not considered to be committed. Any other failure to run git (including git not being installed) is returned
+// as an error.
+func goWorkCommitted(ctx context.Context, dir string) (bool, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-files", "--", "go.work")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return false, errors.Wrapf(ctxErr, "command %v did not complete", cmd.Args)
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if exitErr.ExitCode() == 128 && strings.Contains(string(exitErr.Stderr), "not a git repository") {
+				return false, nil
+			}
+			return false, errors.Wrapf(err, "command %v failed: %s", cmd.Args, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return false, errors.Wrapf(err, "failed to execute command %v", cmd.Args)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
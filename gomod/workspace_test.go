@@ -0,0 +1,209 @@
+// Copyright (c) 2026 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceCopy(t *testing.T) {
+	rootDir := t.TempDir()
+	projectDir := filepath.Join(rootDir, "project")
+	for relPath, content := range map[string]string{
+		"project/go.work":              "go 1.22\n\nuse (\n\t.\n\t./tools\n\t../shared\n)\n",
+		"project/go.mod":               "module github.com/mod/test\n",
+		"project/main.go":              "package main\n",
+		"project/README.md":            "# project\n",
+		"project/internal/lib.go":      "package internal\n",
+		"project/testdata/src/foo.go":  "package foo\n",
+		"project/_examples/example.go": "package main\n",
+		"project/.vendor-123/foo.go":   "package foo\n",
+		"project/nested/go.mod":        "module github.com/mod/test/nested\n",
+		"project/nested/nested.go":     "package nested\n",
+		"project/tools/tools.go":       "package tools\n",
+		"project/vendor/modules.txt":   "# github.com/pkg/errors v0.9.1\n",
+		"project/.git/HEAD":            "ref: refs/heads/main\n",
+		"project/tools/go.mod":         "module github.com/mod/test/tools\n",
+		"project/tools/go.sum":         "github.com/pkg/errors v0.9.1 h1:hash=\n",
+		"project/tools/vendor/foo.go":  "package foo\n",
+		"shared/go.mod":                "module github.com/mod/shared\n",
+		"shared/internal/shared.go":    "package internal\n",
+		"unrelated/should-not-copy.go": "package unrelated\n",
+	} {
+		path := filepath.Join(rootDir, filepath.FromSlash(relPath))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	ws, err := readWorkspace(projectDir)
+	require.NoError(t, err)
+
+	// overrides of files that are not part of the workspace are ignored
+	overrides := []fileChange{
+		{path: filepath.Join(projectDir, "go.sum"), after: []byte("github.com/pkg/errors v0.9.1 h1:hash=\n")},
+		{path: filepath.Join(projectDir, "tools", "go.sum")},
+		{path: filepath.Join(rootDir, "unrelated", "go.mod"), after: []byte("module github.com/unrelated\n")},
+	}
+	wsCopy, err := newWorkspaceCopy(ws, overrides)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(wsCopy.dir, "project", "tools", "go.mod"), wsCopy.path(filepath.Join(projectDir, "tools", "go.mod")))
+	assert.Equal(t, []string{"GOWORK=" + filepath.Join(wsCopy.dir, "project", "go.work")}, wsCopy.env(ws))
+
+	var got []string
+	require.NoError(t, filepath.WalkDir(wsCopy.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(wsCopy.dir, path)
+		got = append(got, filepath.ToSlash(relPath))
+		return err
+	}))
+	sort.Strings(got)
+	assert.Equal(t, []string{
+		"project/go.mod",
+		"project/go.sum",
+		"project/go.work",
+		"project/internal/lib.go",
+		"project/main.go",
+		"project/tools/go.mod",
+		"project/tools/tools.go",
+		"shared/go.mod",
+		"shared/internal/shared.go",
+	}, got)
+
+	changed, err := wsCopy.changed(ws, overrides)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	require.NoError(t, os.WriteFile(wsCopy.path(filepath.Join(projectDir, "tools", "go.mod")), []byte("module github.com/mod/test/tools\n\ngo 1.22\n"), 0644))
+	require.NoError(t, os.WriteFile(wsCopy.path(filepath.Join(projectDir, "go.work.sum")), []byte("sum\n"), 0644))
+	changed, err = wsCopy.changed(ws, overrides)
+	require.NoError(t, err)
+	assert.Equal(t, []fileChange{
+		{path: filepath.Join(projectDir, "go.work.sum"), after: []byte("sum\n")},
+		{path: filepath.Join(projectDir, "tools", "go.mod"), before: []byte("module github.com/mod/test/tools\n"), after: []byte("module github.com/mod/test/tools\n\ngo 1.22\n")},
+	}, changed)
+
+	// the project is never modified
+	content, err := os.ReadFile(filepath.Join(projectDir, "tools", "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, "module github.com/mod/test/tools\n", string(content))
+
+	require.NoError(t, wsCopy.remove())
+	_, err = os.Stat(wsCopy.dir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWorkspaceCopyLocalReplaces(t *testing.T) {
+	rootDir := t.TempDir()
+	projectDir := filepath.Join(rootDir, "a", "b", "project")
+	for relPath, content := range map[string]string{
+		"a/b/project/go.work":   "go 1.22\n\nuse .\n\nreplace example.com/other => ../../other\n",
+		"a/b/project/go.mod":    "module github.com/mod/test\n\nreplace example.com/dep => ../dep\n",
+		"a/b/dep/go.mod":        "module example.com/dep\n",
+		"a/b/dep/dep.go":        "package dep\n",
+		"a/b/override/go.mod":   "module example.com/override\n",
+		"a/other/go.mod":        "module example.com/other\n",
+		"dep/go.mod":            "module example.com/dep\n",
+		"a/b/unused/go.mod":     "module example.com/unused\n",
+		"a/b/project/main.go":   "package main\n",
+		"a/b/project/.git/HEAD": "ref: refs/heads/main\n",
+	} {
+		path := filepath.Join(rootDir, filepath.FromSlash(relPath))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	ws, err := readWorkspace(projectDir)
+	require.NoError(t, err)
+
+	// the replace directives of the pending go.mod are used, absolute paths and missing directories are ignored
+	overrides := []fileChange{
+		{path: filepath.Join(projectDir, "go.mod"), after: []byte("module github.com/mod/test\n\nreplace (\n\texample.com/dep => ../dep\n\texample.com/override => ../override\n\texample.com/abs => " + filepath.ToSlash(filepath.Join(rootDir, "dep")) + "\n\texample.com/missing => ../missing\n)\n")},
+	}
+	wsCopy, err := newWorkspaceCopy(ws, overrides)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, wsCopy.remove())
+	}()
+	assert.Equal(t, filepath.Join(wsCopy.dir, "b", "dep", "go.mod"), wsCopy.path(filepath.Join(rootDir, "a", "b", "dep", "go.mod")))
+
+	var got []string
+	require.NoError(t, filepath.WalkDir(wsCopy.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(wsCopy.dir, path)
+		got = append(got, filepath.ToSlash(relPath))
+		return err
+	}))
+	sort.Strings(got)
+	assert.Equal(t, []string{
+		"b/dep/dep.go",
+		"b/dep/go.mod",
+		"b/override/go.mod",
+		"b/project/go.mod",
+		"b/project/go.work",
+		"b/project/main.go",
+		"other/go.mod",
+	}, got)
+}
+
+func TestGoWorkCommitted(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	rootDir := t.TempDir()
+	// do not treat a repository that contains the temporary directory as the repository of the test directories
+	t.Setenv("GIT_CEILING_DIRECTORIES", rootDir)
+
+	for i, tc := range []struct {
+		name  string
+		setup []string
+		want  bool
+	}{
+		{
+			name: "not a git repository",
+		},
+		{
+			name:  "go.work is not tracked",
+			setup: []string{"init"},
+		},
+		{
+			name:  "go.work is tracked",
+			setup: []string{"init", "add go.work"},
+			want:  true,
+		},
+	} {
+		dir := filepath.Join(rootDir, fmt.Sprintf("case-%d", i))
+		require.NoError(t, os.MkdirAll(dir, 0755), "Case %d: %s", i, tc.name)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.work"), []byte("go 1.22\n"), 0644), "Case %d: %s", i, tc.name)
+		for _, args := range tc.setup {
+			cmd := exec.Command("git", strings.Fields(args)...)
+			cmd.Dir = dir
+			output, err := cmd.CombinedOutput()
+			require.NoError(t, err, "Case %d: %s: %s", i, tc.name, output)
+		}
+
+		got, err := goWorkCommitted(context.Background(), dir)
+		require.NoError(t, err, "Case %d: %s", i, tc.name)
+		assert.Equal(t, tc.want, got, "Case %d: %s", i, tc.name)
+	}
+}
+
+func TestGoWorkCommittedReportsGitErrors(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	_, err := goWorkCommitted(context.Background(), t.TempDir())
+	assert.Error(t, err)
+}
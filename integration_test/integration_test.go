@@ -9,7 +9,6 @@ import (
 	"os"
 	"os/exec"
 	"path"
-	"strings"
 	"testing"
 
 	"github.com/nmiyake/pkg/dirs"
@@ -275,13 +274,21 @@ func TestModVerifyApplyFalseFails(t *testing.T) {
 	_, err = gofiles.Write(projectDir, specs)
 	require.NoError(t, err)
 
+	goModBefore, err := os.ReadFile("go.mod")
+	require.NoError(t, err)
+
 	outputBuf = &bytes.Buffer{}
 	runPluginCleanup, err = pluginapitester.RunPlugin(pluginapitester.NewPluginProvider(pluginPath), nil, "mod", []string{"--verify"}, projectDir, false, outputBuf)
 	defer runPluginCleanup()
 	require.Error(t, err)
 
 	output := outputBuf.String()
-	assert.True(t, strings.HasSuffix(output, "Error: go.mod modified\n"), output)
+	assert.Contains(t, output, "Error: go.mod modified:\n--- a/go.mod\n+++ b/go.mod\n")
+	assert.Contains(t, output, "+\tgithub.com/pkg/math ")
+
+	goModAfter, err := os.ReadFile("go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, string(goModBefore), string(goModAfter), "verify should not modify go.mod")
 }
 
 func TestModVerifyApplyFalseFailsWithVendor(t *testing.T) {
@@ -356,7 +363,8 @@ func TestModVerifyApplyFalseFailsWithVendor(t *testing.T) {
 	require.Error(t, err)
 
 	output := outputBuf.String()
-	assert.True(t, strings.HasSuffix(output, "Error: go.mod modified\n"), output)
+	assert.Contains(t, output, "Error: go.mod modified:\n--- a/go.mod\n+++ b/go.mod\n")
+	assert.Contains(t, output, "+\tgithub.com/pkg/math ")
 }
 
 func TestModVerifyApplyFalseWithVendorSucceedsWithNoModDependencies(t *testing.T) {